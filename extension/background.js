@@ -289,14 +289,14 @@ function combineAndSend() {
 
   const data = frameData[0];
   console.log('Sending', data.contentLength, 'chars');
-  sendToHost(data.text);
+  sendToHost(data.text, data.jsonLd);
 
   frameData = [];
 }
 
 // ========== NATIVE HOST ==========
 
-async function sendToHost(text) {
+async function sendToHost(text, jsonLd = []) {
   const storage = chrome.storage; // Firefox supports chrome.* alias
 
   const defaults = {
@@ -312,6 +312,10 @@ async function sendToHost(text) {
         port = chrome.runtime.connectNative('com.textextractor.host');
 
         port.onMessage.addListener((msg) => {
+          if (msg && msg.code === 'budget_exceeded') {
+            console.warn('✗ Provider budget exceeded, nothing was sent:', msg.error);
+            return;
+          }
           console.log('✓ Host response:', msg);
         });
 
@@ -326,6 +330,7 @@ async function sendToHost(text) {
       port.postMessage({
         text: text,
         settings: settings,
+        jsonLd: jsonLd,
       });
       console.log('✓ Sent to host with settings:', settings.provider);
     } catch (err) {
@@ -460,13 +460,20 @@ SOURCE: ${source} (Scraped)
 ${text}
 `;
   
+  // schema.org JobPosting data, parsed by the native host
+  const jsonLd = Array.from(
+    document.querySelectorAll('script[type="application/ld+json"]'),
+    el => el.textContent
+  );
+
   chrome.runtime.sendMessage({
     action: "extractText",
     data: {
       text: formatted,
       url: window.location.href,
       title: document.title,
-      contentLength: text.length
+      contentLength: text.length,
+      jsonLd: jsonLd
     }
   }).catch(err => console.error("[Content] Send failed:", err));
 }
@@ -35,6 +35,10 @@ const jobsListEl = document.getElementById('jobsList');
 const jobDetailEl = document.getElementById('jobDetail');
 const searchInputEl = document.getElementById('searchInput');
 const statusFilterEl = document.getElementById('statusFilter');
+const languageFilterEl = document.getElementById('languageFilter');
+const analyticsLanguageEl = document.getElementById('analyticsLanguage');
+const reviewFilterEl = document.getElementById('reviewFilter');
+const analyticsIncludeUnreviewedEl = document.getElementById('analyticsIncludeUnreviewed');
 
 let allJobs = [];
 let currentJobId = null;
@@ -53,6 +57,8 @@ let chartSkillsByStatus = null;
 function renderJobs() {
   const search = (searchInputEl.value || '').toLowerCase();
   const statusFilter = statusFilterEl.value;
+  const languageFilter = languageFilterEl.value;
+  const reviewFilter = reviewFilterEl.value;
 
   jobsListEl.innerHTML = '';
 
@@ -61,7 +67,9 @@ function renderJobs() {
       job.title.toLowerCase().includes(search) ||
       job.company.toLowerCase().includes(search);
     const matchesStatus = !statusFilter || job.status === statusFilter;
-    return matchesSearch && matchesStatus;
+    const matchesLanguage = !languageFilter || job.language === languageFilter;
+    const matchesReview = !reviewFilter || job.needsReview;
+    return matchesSearch && matchesStatus && matchesLanguage && matchesReview;
   });
 
   if (filtered.length === 0) {
@@ -83,11 +91,12 @@ function renderJobs() {
 
     const title = document.createElement('div');
     title.className = 'job-title';
-    title.textContent = job.title;
+    title.textContent = job.suspicious ? `⚠ ${job.title}` : job.title;
 
     const meta = document.createElement('div');
     meta.className = 'job-meta';
     meta.textContent = `${job.company} · ${job.location || 'Location not set'}`;
+    if (job.needsReview) meta.textContent += ' · Needs review';
 
     main.appendChild(title);
     main.appendChild(meta);
@@ -161,6 +170,19 @@ async function updateJobStatus(jobId, status) {
   }
 }
 
+async function approveExtraction(jobId, buttonEl) {
+  try {
+    if (buttonEl) buttonEl.disabled = true;
+    await sendNativeMessage({ action: 'approveExtraction', data: { id: jobId } });
+    const job = allJobs.find((j) => j.id === jobId);
+    if (job) job.needsReview = false;
+    await openJobDetail(jobId);
+  } catch (err) {
+    console.error('Failed to approve extraction', err);
+    if (buttonEl) buttonEl.disabled = false;
+  }
+}
+
 async function deleteJob(jobId, buttonEl) {
   if (!confirm('Remove this job from your list?')) return;
   try {
@@ -241,6 +263,56 @@ async function openJobDetail(jobId) {
     link.style.display = url && url !== '#' ? 'inline-block' : 'none';
     link.style.marginBottom = '8px';
 
+    // The posting tried to instruct the extraction model; the host flags
+    // rather than trusts such jobs.
+    const injectionWarning = document.createElement('p');
+    injectionWarning.className = 'job-warning';
+    injectionWarning.textContent =
+      '⚠ This posting contains text aimed at the extraction model (' +
+      (job.injectionFlags || []).join(', ') +
+      '). Double-check the extracted fields.';
+    injectionWarning.style.display =
+      job.injectionFlags && job.injectionFlags.length ? 'block' : 'none';
+
+    // Extracted values the host could not find in the posting text.
+    const ungrounded = job.ungrounded || [];
+    const groundingWarning = document.createElement('p');
+    groundingWarning.className = 'job-warning';
+    groundingWarning.textContent =
+      '⚠ Not found in the posting text: ' +
+      ungrounded.map((c) => `${c.value} (${c.field.split('.').pop()})`).join(', ') +
+      '.';
+    groundingWarning.style.display = ungrounded.length ? 'block' : 'none';
+
+    // Extractions with missing, invalid or unlikely values wait for an
+    // approval before they count in analytics.
+    const reviewKinds = {
+      missing: 'missing',
+      invalid: 'invalid',
+      suspicious: 'unlikely',
+      ungrounded: 'not in posting',
+      injection: 'text aimed at the model',
+    };
+    const reviewBox = document.createElement('div');
+    reviewBox.className = 'job-warning';
+    reviewBox.style.display = job.needsReview ? 'block' : 'none';
+    const reviewText = document.createElement('span');
+    reviewText.textContent =
+      'Needs review: ' +
+      (job.reviewReasons || [])
+        .map((r) => {
+          const [kind, field] = r.split(':');
+          const what = reviewKinds[kind] || kind;
+          return field ? `${field.split('.').pop()} (${what})` : what;
+        })
+        .join(', ') +
+      '. Left out of analytics until approved. ';
+    const approveBtn = document.createElement('button');
+    approveBtn.textContent = 'Approve extraction';
+    approveBtn.addEventListener('click', () => approveExtraction(job.id, approveBtn));
+    reviewBox.appendChild(reviewText);
+    reviewBox.appendChild(approveBtn);
+
     const skills = document.createElement('div');
     skills.innerHTML = `<strong>Skills:</strong> ${(job.skills || []).join(', ') || 'None extracted'
       }`;
@@ -351,6 +423,40 @@ async function openJobDetail(jobId) {
       <p><strong>Extracted at:</strong> ${extracted.extracted_at || ''}</p>
     `;
 
+    // The passages the model says key fields came from. Quotes are page
+    // text, so they are set as text rather than HTML.
+    const evidenceLabels = {
+      salary: 'Salary',
+      seniority_level: 'Seniority',
+      workplace_type: 'Workplace type',
+      years_experience: 'Experience',
+      visa_sponsorship: 'Visa sponsorship',
+    };
+    const evidenceSection = document.createElement('div');
+    const evidence = job.evidence || [];
+    if (evidence.length) {
+      const heading = document.createElement('h3');
+      heading.textContent = 'Why';
+      evidenceSection.appendChild(heading);
+      evidence.forEach((ev) => {
+        const p = document.createElement('p');
+        const label = document.createElement('strong');
+        label.textContent = `${evidenceLabels[ev.field] || ev.field}: `;
+        const quote = document.createElement('span');
+        quote.className = 'evidence-quote';
+        quote.textContent = `“${ev.quote}”`;
+        p.appendChild(label);
+        p.appendChild(quote);
+        if (!ev.found) {
+          const note = document.createElement('span');
+          note.className = 'job-meta';
+          note.textContent = ' (not found verbatim in the posting)';
+          p.appendChild(note);
+        }
+        evidenceSection.appendChild(p);
+      });
+    }
+
     const notesWrapper = document.createElement('div');
     const notesLabel = document.createElement('label');
     notesLabel.textContent = 'Notes';
@@ -374,6 +480,9 @@ async function openJobDetail(jobId) {
 
     jobDetailEl.appendChild(headerRow);
     jobDetailEl.appendChild(link);
+    jobDetailEl.appendChild(injectionWarning);
+    jobDetailEl.appendChild(groundingWarning);
+    jobDetailEl.appendChild(reviewBox);
     jobDetailEl.appendChild(skills);
     jobDetailEl.appendChild(document.createElement('hr'));
     jobDetailEl.appendChild(metaSection);
@@ -383,6 +492,7 @@ async function openJobDetail(jobId) {
     jobDetailEl.appendChild(compSection);
     jobDetailEl.appendChild(workSection);
     jobDetailEl.appendChild(marketSection);
+    jobDetailEl.appendChild(evidenceSection);
     jobDetailEl.appendChild(document.createElement('hr'));
     jobDetailEl.appendChild(notesWrapper);
   } catch (err) {
@@ -406,12 +516,16 @@ async function loadAnalytics() {
   summaryEl.textContent = 'Loading analytics...';
 
   try {
-    const resp = await sendNativeMessage({ action: 'getAnalytics' });
+    const language = analyticsLanguageEl.value;
+    const data = { includeUnreviewed: analyticsIncludeUnreviewedEl.checked };
+    if (language) data.language = language;
+    const resp = await sendNativeMessage({ action: 'getAnalytics', data });
 
     const statusStats = resp.statusStats || {};
     const skillsByCategory = resp.skillsByCategory || {};
     const skillsByStatus = resp.skillsByStatus || {};
     const topJobTitles = resp.topJobTitles || [];
+    const postingLanguages = resp.postingLanguages || [];
 
     const total = statusStats.total || 0;
     summaryEl.textContent =
@@ -419,6 +533,18 @@ async function loadAnalytics() {
       `Applied: ${statusStats.applied || 0}, ` +
       `Interview: ${statusStats.interview || 0}, ` +
       `Offer: ${statusStats.offer || 0}.`;
+    if (postingLanguages.length > 0) {
+      summaryEl.textContent +=
+        ' Languages: ' +
+        postingLanguages
+          .map((l) => `${l.name || 'unknown'} ${l.count}`)
+          .join(', ') +
+        '.';
+    }
+    if (resp.unreviewedJobs && !analyticsIncludeUnreviewedEl.checked) {
+      summaryEl.textContent +=
+        ` ${resp.unreviewedJobs} jobs awaiting review are left out of the charts.`;
+    }
 
     // Helper to build a simple bar chart
     function buildBarChart(chartRef, canvasId, labels, data, label, color) {
@@ -621,6 +747,10 @@ testHostBtn.addEventListener('click', async () => {
 // Event bindings
 searchInputEl.addEventListener('input', renderJobs);
 statusFilterEl.addEventListener('change', renderJobs);
+languageFilterEl.addEventListener('change', renderJobs);
+analyticsLanguageEl.addEventListener('change', loadAnalytics);
+reviewFilterEl.addEventListener('change', renderJobs);
+analyticsIncludeUnreviewedEl.addEventListener('change', loadAnalytics);
 
 // Initial load
 loadJobs();
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+)
+
+const cacheUsage = "cache [stats | evict [-unused-for DURATION] [-provider NAME] [-model NAME] [-prompt VERSION] [-all]]"
+
+// runCache shows what the extraction cache holds, or evicts entries from it.
+func runCache(args []string, cfg *config.Config, database *db.DB) error {
+	if len(args) == 0 || args[0] == "stats" {
+		return printCacheStats(database)
+	}
+	if args[0] != "evict" {
+		return fmt.Errorf("usage: job-extractor %s", cacheUsage)
+	}
+
+	fs := flag.NewFlagSet("cache evict", flag.ContinueOnError)
+	unusedFor := fs.Duration("unused-for", 0, "only entries neither stored nor used for this long, e.g. 720h")
+	provider := fs.String("provider", "", "only entries of this provider")
+	model := fs.String("model", "", "only entries of this model")
+	prompt := fs.String("prompt", "", "only entries of this prompt version")
+	all := fs.Bool("all", false, "evict everything")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	filter := db.CacheFilter{Provider: *provider, Model: *model, PromptVersion: *prompt}
+	if *unusedFor > 0 {
+		filter.UnusedSince = time.Now().Add(-*unusedFor)
+	}
+	if filter == (db.CacheFilter{}) && !*all {
+		return fmt.Errorf("no filter given; pass -all to empty the cache")
+	}
+
+	n, err := database.EvictCache(filter)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Evicted %d cached extractions\n", n)
+	return nil
+}
+
+func printCacheStats(database *db.DB) error {
+	stats, err := database.GetCacheStats()
+	if err != nil {
+		return err
+	}
+	if len(stats) == 0 {
+		fmt.Println("The extraction cache is empty.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tMODEL\tPROMPT\tENTRIES\tHITS")
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n", s.Provider, s.Model, s.PromptVersion, s.Entries, s.Hits)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"native-host/internal/calendar"
+	"native-host/internal/config"
+	"native-host/internal/db"
+)
+
+// exportCalendar writes the events of one job (jobID > 0) or of the whole
+// pipeline to an .ics file in the output directory and returns its path.
+// File names are fixed so that re-exports overwrite the previous file.
+func exportCalendar(cfg *config.Config, database *db.DB, jobID int64) (string, int, error) {
+	entries, err := database.ListCalendarEntries(jobID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	events := make([]calendar.Event, 0, len(entries))
+	for _, e := range entries {
+		events = append(events, calendarEvent(e))
+	}
+
+	name := "JobFlow pipeline"
+	filename := "jobflow_pipeline.ics"
+	if jobID > 0 {
+		name = "JobFlow"
+		if len(entries) > 0 {
+			name = fmt.Sprintf("JobFlow: %s", jobLabel(entries[0]))
+		}
+		filename = fmt.Sprintf("job_%d.ics", jobID)
+	}
+
+	dir := filepath.Join(cfg.OutputDir, "calendar")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", 0, fmt.Errorf("create calendar dir: %w", err)
+	}
+	path := filepath.Join(dir, filename)
+
+	// Write to a temp file first so a calendar app polling the file never
+	// sees a half-written feed.
+	tmp, err := os.CreateTemp(dir, filename+".*")
+	if err != nil {
+		return "", 0, fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := calendar.Write(tmp, name, events); err != nil {
+		tmp.Close()
+		return "", 0, fmt.Errorf("write calendar: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", 0, fmt.Errorf("close calendar: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, fmt.Errorf("rename calendar: %w", err)
+	}
+
+	return path, len(events), nil
+}
+
+// calendarEvent maps a DB entry to a VEVENT. UIDs only depend on the row
+// id, so they survive edits; the sequence number is the row's revision.
+func calendarEvent(e db.CalendarEntry) calendar.Event {
+	ev := calendar.Event{
+		UID:       fmt.Sprintf("%s-%d@jobflow.local", e.Source, e.ID),
+		URL:       e.SourceURL,
+		Start:     e.StartsAt,
+		End:       e.EndsAt,
+		AllDay:    e.AllDay,
+		Cancelled: e.Cancelled,
+		Sequence:  e.Revision,
+		Modified:  e.UpdatedAt,
+	}
+
+	var desc []string
+	switch e.Source {
+	case "interview":
+		ev.Summary = fmt.Sprintf("Interview (%s): %s", strings.ReplaceAll(e.Kind, "_", " "), jobLabel(e))
+		if len(e.Attendees) > 0 {
+			desc = append(desc, "Interviewers: "+strings.Join(e.Attendees, "; "))
+		}
+	default:
+		title := e.Title
+		if title == "" {
+			title = eventKindLabels[e.Kind]
+		}
+		ev.Summary = fmt.Sprintf("%s: %s", title, jobLabel(e))
+	}
+	if e.Notes != "" {
+		desc = append(desc, e.Notes)
+	}
+	if e.SourceURL != "" {
+		desc = append(desc, e.SourceURL)
+	}
+	ev.Description = strings.Join(desc, "\n\n")
+
+	return ev
+}
+
+var eventKindLabels = map[string]string{
+	"follow_up":    "Follow up",
+	"deadline":     "Deadline",
+	"closing_date": "Applications close",
+	"other":        "Reminder",
+}
+
+func jobLabel(e db.CalendarEntry) string {
+	switch {
+	case e.JobTitle != "" && e.CompanyName != "":
+		return e.JobTitle + " @ " + e.CompanyName
+	case e.JobTitle != "":
+		return e.JobTitle
+	case e.CompanyName != "":
+		return e.CompanyName
+	default:
+		return fmt.Sprintf("job #%d", e.JobID)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"native-host/internal/db"
+	"native-host/internal/grounding"
+	"native-host/internal/models"
+	"native-host/internal/review"
+)
+
+// checkExtraction checks a job's extracted values against the text they
+// were extracted from, locates the model's evidence quotes in it and
+// decides whether the extraction needs review, storing all three. text is
+// empty when the job has no stored raw text; only the review is updated
+// then. fresh is set for a new extraction, which clears an earlier
+// approval. Failures are logged; a job without checks simply shows none.
+func checkExtraction(database *db.DB, jobID int64, job *models.JobPosting, text string, known *models.JobPosting, fresh bool) {
+	var checks []grounding.FieldCheck
+	if text != "" {
+		checks = grounding.Check(job, text, known)
+		for _, c := range checks {
+			if !c.Grounded {
+				log.Printf("Job %d: %s not found in the posting text", jobID, c)
+			}
+		}
+		if err := database.SaveFieldChecks(jobID, checks); err != nil {
+			log.Printf("Error saving field checks for job %d: %v", jobID, err)
+		}
+
+		spans := grounding.Spans(job, text)
+		for _, s := range spans {
+			if !s.Found() {
+				log.Printf("Job %d: evidence for %s not found in the posting text: %q", jobID, s.Field, s.Quote)
+			}
+		}
+		if err := database.SaveEvidence(jobID, spans); err != nil {
+			log.Printf("Error saving evidence for job %d: %v", jobID, err)
+		}
+	}
+
+	reasons := review.Reasons(job, checks)
+	if len(reasons) > 0 {
+		log.Printf("Job %d needs review: %s", jobID, strings.Join(reasons, ", "))
+	}
+	if err := database.SetReviewReasons(jobID, reasons, fresh); err != nil {
+		log.Printf("Error saving review reasons for job %d: %v", jobID, err)
+	}
+}
+
+func fieldCheckPayload(c grounding.FieldCheck) map[string]any {
+	return map[string]any{
+		"field":      c.Field,
+		"value":      c.Value,
+		"grounded":   c.Grounded,
+		"confidence": c.Confidence,
+	}
+}
+
+// evidencePayload leaves out the offsets of quotes that weren't found.
+func evidencePayload(s grounding.Span) map[string]any {
+	p := map[string]any{
+		"field": s.Field,
+		"quote": s.Quote,
+		"found": s.Found(),
+	}
+	if s.Found() {
+		p["start"], p["end"] = s.Start, s.End
+	}
+	return p
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+)
+
+// command is a CLI subcommand. args excludes the program and command name.
+type command struct {
+	usage string
+	run   func(args []string, cfg *config.Config, database *db.DB) error
+}
+
+// commands are matched by exact name on os.Args[1]. Firefox starts the
+// native host with the manifest path as first argument, which never
+// collides with these.
+var commands = map[string]command{
+	"cache": {
+		usage: cacheUsage,
+		run:   runCache,
+	},
+	"compare": {
+		usage: compareUsage,
+		run:   runCompare,
+	},
+	"extract-url": {
+		usage: extractURLUsage,
+		run:   runExtractURL,
+	},
+	"import": {
+		usage: importUsage,
+		run:   runImport,
+	},
+	"liveness": {
+		usage: livenessUsage,
+		run:   runLiveness,
+	},
+	"reextract": {
+		usage: reextractUsage,
+		run:   runReextract,
+	},
+	"reminders": {
+		usage: remindersUsage,
+		run:   runReminders,
+	},
+}
+
+// runCLI executes the command named by args[0] and reports whether args
+// named a command at all.
+func runCLI(args []string, cfg *config.Config, database *db.DB) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage()
+		return true
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return false
+	}
+
+	if database == nil {
+		fmt.Fprintln(os.Stderr, "database not initialized; see", cfg.LogPath)
+		os.Exit(1)
+	}
+	if err := cmd.run(args[1:], cfg, database); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	return true
+}
+
+func printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: job-extractor <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "Without a command it runs as the Firefox native messaging host.")
+	fmt.Fprintln(os.Stderr)
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "  job-extractor "+commands[name].usage)
+	}
+}
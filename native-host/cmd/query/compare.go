@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+	"native-host/internal/extractor"
+	"native-host/internal/models"
+)
+
+const compareUsage = "compare [-id N | -file PATH] [-known=false] SPEC... (SPEC is provider[:model][@prompt], e.g. ollama:qwen2.5:7b@v1)"
+
+type compareRun struct {
+	Spec   string
+	Result *extractor.Result
+	Err    error
+}
+
+// runCompare extracts one posting with several providers/models in turn
+// and reports where they disagree. Nothing is saved.
+func runCompare(args []string, cfg *config.Config, database *db.DB) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "use the stored raw text of this job")
+	file := fs.String("file", "", "use the posting text in this file")
+	useKnown := fs.Bool("known", true, "pass fields parsed from the page's structured data, as extraction does")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*id == 0) == (*file == "") || fs.NArg() < 1 {
+		return fmt.Errorf("usage: %s", compareUsage)
+	}
+
+	var text string
+	var known *models.JobPosting
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		text = string(data)
+	} else {
+		texts, err := database.ListRawTexts(*id)
+		if err != nil {
+			return fmt.Errorf("job %d: %w", *id, err)
+		}
+		if texts[0].Text == "" {
+			return fmt.Errorf("job %d: %w; run reextract first to backfill it", *id, errNoRawText)
+		}
+		text, known = texts[0].Text, texts[0].Known
+	}
+	if !*useKnown {
+		known = nil
+	}
+
+	runs := make([]compareRun, 0, fs.NArg())
+	for _, spec := range fs.Args() {
+		run := compareRun{Spec: spec}
+		target, promptVersion := spec, ""
+		if i := strings.LastIndex(spec, "@"); i >= 0 {
+			target, promptVersion = spec[:i], spec[i+1:]
+		}
+		provider, model, _ := strings.Cut(target, ":")
+		settings, err := providerSettings(provider, model, promptVersion)
+		settings.NoCache = true // latency and usage are part of the comparison
+		if err == nil {
+			fmt.Fprintf(os.Stderr, "Running %s...\n", spec)
+			run.Result, err = extractor.Run(text, settings, known)
+			recordRun(cfg, database, db.RunCompare, *id, run.Result, err)
+		}
+		run.Err = err
+		runs = append(runs, run)
+	}
+
+	return printComparison(runs)
+}
+
+func printComparison(runs []compareRun) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SPEC\tMODEL\tPROMPT\tLATENCY\tTOKENS IN\tTOKENS OUT\tRESULT")
+	var ok []compareRun
+	for _, r := range runs {
+		if r.Err != nil {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\t-\terror: %v\n", r.Spec, r.Err)
+			continue
+		}
+		ok = append(ok, r)
+		fmt.Fprintf(tw, "%s\t%s/%s\t%s\t%s\t%d\t%d\tok\n", r.Spec, r.Result.Provider, r.Result.Model, r.Result.PromptVersion,
+			r.Result.Latency.Round(10*time.Millisecond), r.Result.Usage.PromptTokens, r.Result.Usage.CompletionTokens)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(ok) < 2 {
+		fmt.Println("\nFewer than two successful runs, nothing to compare.")
+		return nil
+	}
+
+	jobs := make([]*models.JobPosting, len(ok))
+	for i, r := range ok {
+		jobs[i] = r.Result.Job
+	}
+	disagreements, total := models.Disagreements(jobs)
+
+	fmt.Printf("\n%d of %d fields disagree (%.0f%% agreement)\n",
+		len(disagreements), total, 100*float64(total-len(disagreements))/float64(total))
+	tw = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, d := range disagreements {
+		fmt.Fprintf(tw, "\n%s\n", d.Field)
+		for i, v := range d.Values {
+			fmt.Fprintf(tw, "  %s\t%s\n", ok[i].Spec, formatValue(v))
+		}
+	}
+	return tw.Flush()
+}
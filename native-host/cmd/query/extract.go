@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+	"native-host/internal/extractor"
+	"native-host/internal/jsonld"
+	"native-host/internal/models"
+	"native-host/internal/parsers"
+	"native-host/internal/webpage"
+)
+
+const (
+	// userAgent is sent on every request the host makes to job boards.
+	userAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+
+	extractURLUsage   = "extract-url [-provider ollama|perplexity] [-model NAME] [-prompt VERSION] [-no-cache] URL"
+	pageFetchTimeout  = 30 * time.Second
+	minPageTextLength = 200
+)
+
+var pageClient = &http.Client{Timeout: pageFetchTimeout}
+
+// extraction is the outcome of one run of the extraction pipeline.
+type extraction struct {
+	Job      *models.JobPosting
+	JobID    int64 // 0 when the database is unavailable or the save failed
+	RawPath  string
+	JSONPath string
+}
+
+// extractAndSave runs the full extraction flow: save the raw text, call
+// Perplexity/Ollama, save to the DB and write the structured JSON. known
+// holds fields parsed from the page's structured data, or nil. A failed DB
+// save is logged but not fatal, so the files are still written.
+func extractAndSave(text string, settings models.Settings, known *models.JobPosting, cfg *config.Config, database *db.DB) (*extraction, error) {
+	res := &extraction{}
+
+	// Save raw text
+	prefix, err := writeRawFile(cfg.OutputDir, text)
+	if err != nil {
+		return res, fmt.Errorf("write raw file: %w", err)
+	}
+	rawPath := prefix + "_raw.txt"
+	res.RawPath = rawPath
+	log.Printf("Saved raw text to %s", rawPath)
+
+	// Extract structured data
+	log.Printf("Calling %s for structured extraction...", settings.Provider)
+	run, err := extractor.Run(text, settings, known)
+	if err != nil {
+		recordRun(cfg, database, db.RunExtract, 0, run, err)
+		return res, fmt.Errorf("extract with %s: %w", settings.Provider, err)
+	}
+	job := run.Job
+	if settings.SourceURL != "" {
+		job.SourceURL = settings.SourceURL
+	}
+	res.Job = job
+
+	// Save to database (if available)
+	if database != nil {
+		previous := savedJob(database, job.SourceURL)
+		jobID, err := database.SaveJob(job)
+		if err != nil {
+			log.Printf("Error saving to database: %v", err)
+		} else {
+			res.JobID = jobID
+			log.Printf("Saved to database with ID: %d", jobID)
+			if err := database.SaveRawText(jobID, text, rawPath, known); err != nil {
+				log.Printf("Error saving raw text: %v", err)
+			}
+			// A cached result, or a new extraction that changed nothing,
+			// keeps an earlier approval.
+			fresh := !run.Cached && (previous == nil || len(models.Diff(previous, job)) > 0)
+			checkExtraction(database, jobID, job, text, known, fresh)
+		}
+	} else {
+		log.Printf("Database not initialized, skipping save")
+	}
+	recordRun(cfg, database, db.RunExtract, res.JobID, run, nil)
+	if run.FallbackFrom != "" {
+		log.Printf("%s was over budget, extracted with %s/%s instead", run.FallbackFrom, run.Provider, run.Model)
+	}
+
+	// Save structured JSON
+	jsonData, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return res, fmt.Errorf("marshal JSON: %w", err)
+	}
+	jsonPath := prefix + "_structured.json"
+	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
+		return res, fmt.Errorf("write JSON file: %w", err)
+	}
+	res.JSONPath = jsonPath
+	log.Printf("Saved structured data to %s", jsonPath)
+
+	return res, nil
+}
+
+// savedJob returns the job already saved from sourceURL, or nil when there
+// is none or it can't be read.
+func savedJob(database *db.DB, sourceURL string) *models.JobPosting {
+	if sourceURL == "" {
+		return nil
+	}
+	id, err := database.FindJobByURL(sourceURL)
+	if err != nil || id == 0 {
+		return nil
+	}
+	job, _, err := database.GetJobByID(id)
+	if err != nil {
+		log.Printf("Error reading job %d: %v", id, err)
+		return nil
+	}
+	return job
+}
+
+// errorCode maps the errors the extension handles specially to a stable
+// code, "" for any other error.
+func errorCode(err error) string {
+	if errors.Is(err, extractor.ErrBudgetExceeded) {
+		return "budget_exceeded"
+	}
+	return ""
+}
+
+// writeRawFile writes text to job_<timestamp>_raw.txt and returns the path
+// without the "_raw.txt" suffix, for the structured JSON to share. Several
+// extractions can finish within the same second (the import command runs
+// them in parallel), so a counter is appended rather than overwriting.
+func writeRawFile(dir, text string) (string, error) {
+	prefix := filepath.Join(dir, "job_"+time.Now().Format("2006-01-02_15-04-05"))
+	for i := 1; ; i++ {
+		candidate := prefix
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", prefix, i)
+		}
+		f, err := os.OpenFile(candidate+"_raw.txt", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.WriteString(text); err != nil {
+			f.Close()
+			return "", err
+		}
+		return candidate, f.Close()
+	}
+}
+
+// extractURL fetches a posting page, reduces it to the posting text and
+// runs it through the extraction pipeline. The source URL is the one the
+// page was finally served from.
+func extractURL(ctx context.Context, rawURL string, settings models.Settings, cfg *config.Config, database *db.DB) (*extraction, error) {
+	page, text, err := fetchPosting(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return extractPage(page, text, settings, cfg, database)
+}
+
+// fetchPosting downloads a posting page and returns it together with the
+// text to hand to the model.
+func fetchPosting(ctx context.Context, rawURL string) (*webpage.Page, string, error) {
+	page, err := webpage.Fetch(ctx, pageClient, userAgent, rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	text := webpage.FormatForExtraction(page)
+	if len(webpage.MainText(page.Doc)) < minPageTextLength {
+		// Most likely a page rendered client-side; the extension's content
+		// script sees the rendered DOM and should be used instead.
+		return nil, "", fmt.Errorf("no posting text found on %s", page.FinalURL)
+	}
+	log.Printf("Fetched %s: %d bytes of text", page.FinalURL, len(text))
+	return page, text, nil
+}
+
+func extractPage(page *webpage.Page, text string, settings models.Settings, cfg *config.Config, database *db.DB) (*extraction, error) {
+	known := knownFields(page)
+	settings.SourceURL = page.FinalURL
+	return extractAndSave(text, settings, known, cfg, database)
+}
+
+// knownFields collects what can be read from the page without a model:
+// schema.org JSON-LD first, then the job board's own markup, which wins
+// where both are present.
+func knownFields(page *webpage.Page) *models.JobPosting {
+	known := jsonld.FromDocument(page.Doc)
+	if known != nil {
+		log.Printf("Found schema.org JobPosting on %s", page.FinalURL)
+	}
+
+	parsed, err := parsers.Parse(page.FinalURL, page.Doc)
+	if err != nil {
+		log.Printf("Site parser failed, relying on the model: %v", err)
+		return known
+	}
+	if parsed == nil {
+		return known
+	}
+	if known == nil {
+		return parsed
+	}
+	known.Merge(parsed)
+	return known
+}
+
+// settingsFromData decodes the extension's settings object.
+func settingsFromData(data map[string]any) (models.Settings, error) {
+	var settings models.Settings
+	raw, ok := data["settings"]
+	if !ok {
+		return settings, nil
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(b, &settings); err != nil {
+		return settings, fmt.Errorf("invalid settings: %w", err)
+	}
+	return settings, nil
+}
+
+// providerFlags registers -provider, -model, -prompt and -no-cache on fs. The
+// returned function builds the settings once fs has been parsed.
+func providerFlags(fs *flag.FlagSet) func() (models.Settings, error) {
+	provider := fs.String("provider", "ollama", "ollama or perplexity; the Perplexity key is read from PERPLEXITY_API_KEY")
+	model := fs.String("model", "", "model name (provider default when empty)")
+	prompt := fs.String("prompt", "", "prompt version (current default when empty)")
+	noCache := fs.Bool("no-cache", false, "call the provider even when the posting was extracted with the same settings before")
+
+	return func() (models.Settings, error) {
+		settings, err := providerSettings(*provider, *model, *prompt)
+		settings.NoCache = *noCache
+		return settings, err
+	}
+}
+
+// providerSettings builds extraction settings for CLI commands, which have
+// no access to the key stored in the extension.
+func providerSettings(provider, model, promptVersion string) (models.Settings, error) {
+	settings := models.Settings{Provider: provider, PromptVersion: promptVersion}
+	switch provider {
+	case "perplexity":
+		settings.PerplexityKey = os.Getenv("PERPLEXITY_API_KEY")
+		settings.PerplexityModel = model
+		if settings.PerplexityKey == "" {
+			return settings, fmt.Errorf("PERPLEXITY_API_KEY is not set")
+		}
+	case "ollama":
+		settings.OllamaModel = model
+	default:
+		return settings, fmt.Errorf("unknown provider %q", provider)
+	}
+	return settings, nil
+}
+
+func runExtractURL(args []string, cfg *config.Config, database *db.DB) error {
+	fs := flag.NewFlagSet("extract-url", flag.ContinueOnError)
+	settingsFromFlags := providerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: %s", extractURLUsage)
+	}
+	settings, err := settingsFromFlags()
+	if err != nil {
+		return err
+	}
+
+	res, err := extractURL(context.Background(), fs.Arg(0), settings, cfg, database)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved job %d: %s @ %s\n", res.JobID, res.Job.Metadata.JobTitle, res.Job.CompanyInfo.CompanyName)
+	fmt.Printf("Raw text: %s\nJSON:     %s\n", res.RawPath, res.JSONPath)
+	return nil
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+	"native-host/internal/models"
+	"native-host/internal/urllist"
+)
+
+const (
+	importUsage     = "import [-provider ollama|perplexity] [-model NAME] [-prompt VERSION] [-no-cache] [-format text|csv|bookmarks] [-workers N] [-host-delay DURATION] FILE|-"
+	importWorkers   = 2
+	importHostDelay = 3 * time.Second
+)
+
+// Import outcomes.
+const (
+	importSaved     = "saved"
+	importDuplicate = "duplicate"
+	importFailed    = "failed"
+)
+
+type importResult struct {
+	URL    string
+	Status string
+	JobID  int64
+	Detail string
+}
+
+// hostLimiter spaces out requests to the same host so that importing a
+// list of links from one job board doesn't get us blocked.
+type hostLimiter struct {
+	delay time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newHostLimiter(delay time.Duration) *hostLimiter {
+	return &hostLimiter{delay: delay, next: map[string]time.Time{}}
+}
+
+// Wait blocks until a request to rawURL's host is allowed.
+func (l *hostLimiter) Wait(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := strings.ToLower(u.Hostname())
+
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[host] = at.Add(l.delay)
+	l.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(at)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// importURL fetches and extracts one URL unless it is already saved, either
+// under the given URL or the one it redirects to.
+func importURL(ctx context.Context, rawURL string, settings models.Settings, limiter *hostLimiter, cfg *config.Config, database *db.DB) importResult {
+	res := importResult{URL: rawURL}
+
+	if id, err := database.FindJobByURL(rawURL); err != nil {
+		res.Status, res.Detail = importFailed, err.Error()
+		return res
+	} else if id > 0 {
+		res.Status, res.JobID = importDuplicate, id
+		return res
+	}
+
+	if err := limiter.Wait(ctx, rawURL); err != nil {
+		res.Status, res.Detail = importFailed, err.Error()
+		return res
+	}
+	page, text, err := fetchPosting(ctx, rawURL)
+	if err != nil {
+		res.Status, res.Detail = importFailed, err.Error()
+		return res
+	}
+
+	if page.FinalURL != rawURL {
+		if id, err := database.FindJobByURL(page.FinalURL); err == nil && id > 0 {
+			res.Status, res.JobID = importDuplicate, id
+			res.Detail = "redirects to " + page.FinalURL
+			return res
+		}
+	}
+
+	ext, err := extractPage(page, text, settings, cfg, database)
+	if err != nil {
+		res.Status, res.Detail = importFailed, err.Error()
+		return res
+	}
+	if ext.JobID == 0 {
+		res.Status, res.Detail = importFailed, "extracted but not saved to the database; see "+cfg.LogPath
+		return res
+	}
+	res.Status, res.JobID = importSaved, ext.JobID
+	res.Detail = ext.Job.Metadata.JobTitle
+	if company := ext.Job.CompanyInfo.CompanyName; company != "" {
+		res.Detail += " @ " + company
+	}
+	return res
+}
+
+func runImport(args []string, cfg *config.Config, database *db.DB) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	settingsFromFlags := providerFlags(fs)
+	format := fs.String("format", "", "input format: text, csv or bookmarks (guessed when empty)")
+	workers := fs.Int("workers", importWorkers, "postings processed in parallel")
+	hostDelay := fs.Duration("host-delay", importHostDelay, "minimum delay between requests to the same host")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: %s", importUsage)
+	}
+	settings, err := settingsFromFlags()
+	if err != nil {
+		return err
+	}
+	if *workers < 1 {
+		*workers = 1
+	}
+
+	name := fs.Arg(0)
+	var data []byte
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return err
+	}
+	urls, err := urllist.Parse(data, name, urllist.Format(*format))
+	if err != nil {
+		return err
+	}
+	if len(urls) == 0 {
+		return fmt.Errorf("no URLs found in %s", name)
+	}
+	fmt.Fprintf(os.Stderr, "Importing %d URLs with %d workers\n", len(urls), *workers)
+
+	ctx := context.Background()
+	limiter := newHostLimiter(*hostDelay)
+	results := make([]importResult, len(urls))
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		done    int
+		indexes = make(chan int)
+	)
+	for w := 0; w < *workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = importURL(ctx, urls[i], settings, limiter, cfg, database)
+
+				mu.Lock()
+				done++
+				fmt.Fprintf(os.Stderr, "[%d/%d] %-9s %s\n", done, len(urls), results[i].Status, urls[i])
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range urls {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return printImportSummary(results)
+}
+
+func printImportSummary(results []importResult) error {
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tJOB\tURL\tDETAIL")
+	for _, status := range []string{importSaved, importDuplicate, importFailed} {
+		for _, r := range results {
+			if r.Status != status {
+				continue
+			}
+			job := "-"
+			if r.JobID > 0 {
+				job = fmt.Sprint(r.JobID)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Status, job, r.URL, r.Detail)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d URLs: %d saved, %d duplicates, %d failed\n",
+		len(results), counts[importSaved], counts[importDuplicate], counts[importFailed])
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+	"native-host/internal/liveness"
+)
+
+const (
+	livenessUsage   = "liveness [-id N] [-recheck-closed]"
+	livenessWorkers = 4
+	livenessTimeout = 20 * time.Second
+)
+
+var livenessChecker = &liveness.Checker{
+	Client:    &http.Client{Timeout: livenessTimeout},
+	UserAgent: userAgent,
+}
+
+// livenessCheck pairs a check result with the job it was run for.
+type livenessCheck struct {
+	JobID int64
+	liveness.Result
+}
+
+// checkLiveness re-fetches the posting of one job (id > 0) or of every
+// open job and records the outcome.
+func checkLiveness(ctx context.Context, database *db.DB, id int64, recheckClosed bool) ([]livenessCheck, error) {
+	targets, err := database.ListLivenessTargets(id, recheckClosed)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, len(targets))
+	for i, t := range targets {
+		urls[i] = t.SourceURL
+	}
+	results := livenessChecker.CheckAll(ctx, urls, livenessWorkers)
+
+	checks := make([]livenessCheck, 0, len(results))
+	now := time.Now()
+	for i, res := range results {
+		// Unknown results (timeouts, bot walls) are not recorded so the
+		// job is retried first next time.
+		if res.Status != liveness.StatusUnknown {
+			if err := database.RecordLivenessCheck(targets[i].ID, now, res.Status == liveness.StatusClosed, res.Reason); err != nil {
+				return nil, err
+			}
+		}
+		checks = append(checks, livenessCheck{JobID: targets[i].ID, Result: res})
+	}
+	return checks, nil
+}
+
+func runLiveness(args []string, cfg *config.Config, database *db.DB) error {
+	fs := flag.NewFlagSet("liveness", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "check only this job")
+	recheck := fs.Bool("recheck-closed", false, "also re-check jobs already marked closed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	checks, err := checkLiveness(context.Background(), database, *id, *recheck)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "JOB\tSTATUS\tREASON\tURL")
+	counts := map[string]int{}
+	for _, c := range checks {
+		counts[c.Status]++
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", c.JobID, c.Status, c.Reason, c.URL)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d checked: %d open, %d closed, %d unknown\n",
+		len(checks), counts[liveness.StatusOpen], counts[liveness.StatusClosed], counts[liveness.StatusUnknown])
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"native-host/internal/db"
+	"native-host/internal/liveness"
+	"native-host/internal/models"
+)
+
+func TestCheckLivenessClosesAndReopens(t *testing.T) {
+	var up atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`<html><body><h1>Senior Go Engineer</h1><p>Apply now.</p></body></html>`))
+	}))
+	t.Cleanup(srv.Close)
+
+	database, err := db.Init(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	job := &models.JobPosting{SourceURL: srv.URL + "/jobs/1"}
+	job.Metadata.JobTitle = "Senior Go Engineer"
+	id, err := database.SaveJob(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.Exec(`UPDATE jobs SET updated_at = '2026-01-01 00:00:00' WHERE id = ?`, id); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(recheckClosed bool, wantStatus string) *db.JobRecord {
+		t.Helper()
+		checks, err := checkLiveness(context.Background(), database, id, recheckClosed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(checks) != 1 || checks[0].Status != wantStatus {
+			t.Fatalf("checkLiveness = %+v, want one %s result", checks, wantStatus)
+		}
+		_, rec, err := database.GetJobByID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	rec := check(false, liveness.StatusClosed)
+	if rec.ClosedAt == "" || rec.ClosedReason != "HTTP 404" {
+		t.Fatalf("after a 404: closed_at %q, reason %q", rec.ClosedAt, rec.ClosedReason)
+	}
+
+	// Closed jobs are only re-checked when asked.
+	if checks, err := checkLiveness(context.Background(), database, id, false); err != nil || len(checks) != 0 {
+		t.Fatalf("checkLiveness without -recheck-closed = %+v, %v; want no checks", checks, err)
+	}
+
+	up.Store(true)
+	rec = check(true, liveness.StatusOpen)
+	if rec.ClosedAt != "" || rec.ClosedReason != "" {
+		t.Errorf("after reopening: closed_at %q, reason %q; want both cleared", rec.ClosedAt, rec.ClosedReason)
+	}
+
+	var updatedAt string
+	if err := database.QueryRow(`SELECT updated_at FROM jobs WHERE id = ?`, id).Scan(&updatedAt); err != nil {
+		t.Fatal(err)
+	}
+	if updatedAt != "2026-01-01T00:00:00Z" {
+		t.Errorf("updated_at = %s, want it untouched by liveness checks", updatedAt)
+	}
+}
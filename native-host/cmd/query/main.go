@@ -1,13 +1,14 @@
 package main
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"path/filepath"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -15,6 +16,8 @@ import (
 	"native-host/internal/config"
 	"native-host/internal/db"
 	"native-host/internal/extractor"
+	"native-host/internal/jsonld"
+	"native-host/internal/langdetect"
 	"native-host/internal/messaging"
 	"native-host/internal/models"
 )
@@ -33,11 +36,17 @@ func main() {
 		defer logFile.Close()
 	}
 	log.Println("Native host started")
+	if cfg.SettingsErr != nil {
+		log.Printf("Ignoring settings, using defaults: %v", cfg.SettingsErr)
+	}
 
 	if err := cfg.EnsureDirectories(); err != nil {
 		log.Printf("Error creating directories: %v", err)
 		return
 	}
+	extractor.PromptDir = cfg.PromptDir
+	extractor.MaxPostingChars = cfg.MaxPostingChars
+	extractor.RedactTerms = cfg.RedactTerms
 
 	log.Printf("Initializing database at: %s", cfg.DBPath)
 	database, err := db.Init(cfg.DBPath)
@@ -49,6 +58,19 @@ func main() {
 		log.Printf("Database initialized successfully")
 	}
 
+	var ledger extractor.Ledger
+	if database != nil {
+		ledger = database
+	}
+	extractor.DefaultGuard = extractor.NewGuard(cfg.Budgets, cfg.BudgetFallbackModel, ledger, cfg.Price)
+	if database != nil {
+		extractor.DefaultCache = database
+	}
+
+	if runCLI(os.Args[1:], cfg, database) {
+		return
+	}
+
 	// First, read the raw JSON to decide which kind of message it is
 	// We cannot re-use ReadMessage here because it already unmarshals into models.Message.
 	// We'll read the length + bytes manually, then try to decode into APIRequest first.
@@ -66,7 +88,7 @@ func main() {
 	// Try APIRequest first
 	var apiReq messaging.APIRequest
 	if err := json.Unmarshal(msgBytes, &apiReq); err == nil && apiReq.Action != "" {
-		handleAPIRequest(apiReq, database)
+		handleAPIRequest(apiReq, cfg, database)
 		return
 	}
 
@@ -85,81 +107,21 @@ func handleExtractMessage(message models.Message, cfg *config.Config, database *
 	log.Printf("Received %d bytes of text", len(message.Text))
 	log.Printf("Provider: %s", message.Settings.Provider)
 
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-
-	// Save raw text
-	rawFilename := fmt.Sprintf("job_%s_raw.txt", timestamp)
-	rawPath := filepath.Join(cfg.OutputDir, rawFilename)
-	if err := os.WriteFile(rawPath, []byte(message.Text), 0644); err != nil {
-		log.Printf("Error writing raw file: %v", err)
-		_ = messaging.SendResponse(models.Response{Status: "error", Filename: ""})
-		return
-	}
-	log.Printf("Saved raw text to %s", rawPath)
-
-	// Extract structured data
-	log.Printf("Calling %s for structured extraction...", message.Settings.Provider)
-
-	var (
-		structuredData *models.JobPosting
-		err            error
-	)
-
-	if message.Settings.Provider == "perplexity" {
-		structuredData, err = extractor.ExtractWithPerplexity(message.Text, message.Settings)
-	} else {
-		structuredData, err = extractor.ExtractWithOllama(message.Text, message.Settings)
-	}
-	if message.Settings.SourceURL != "" {
-		structuredData.SourceURL = message.Settings.SourceURL
-	}
-
-	if err != nil {
-		log.Printf("Error extracting with %s: %v", message.Settings.Provider, err)
-		_ = messaging.SendResponse(models.Response{Status: "error", Filename: rawPath})
-		return
-	}
-
-	// Save to database (if available)
-	if database != nil {
-		log.Printf("Attempting to save job to database...")
-		jobID, err := database.SaveJob(structuredData)
-		if err != nil {
-			log.Printf("Error saving to database: %v", err)
-		} else {
-			log.Printf("Saved to database with ID: %d", jobID)
-		}
-	} else {
-		log.Printf("Database not initialized, skipping save")
-	}
-
-	// Save structured JSON
-	jsonFilename := fmt.Sprintf("job_%s_structured.json", timestamp)
-	jsonPath := filepath.Join(cfg.OutputDir, jsonFilename)
-
-	jsonData, err := json.MarshalIndent(structuredData, "", "  ")
+	res, err := extractAndSave(message.Text, message.Settings, jsonld.Parse(message.JSONLD), cfg, database)
 	if err != nil {
-		log.Printf("Error marshaling JSON: %v", err)
-		_ = messaging.SendResponse(models.Response{Status: "error", Filename: rawPath})
-		return
-	}
-
-	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
-		log.Printf("Error writing JSON file: %v", err)
-		_ = messaging.SendResponse(models.Response{Status: "error", Filename: rawPath})
+		log.Printf("Error: %v", err)
+		_ = messaging.SendResponse(models.Response{Status: "error", Filename: res.RawPath, Error: err.Error(), Code: errorCode(err)})
 		return
 	}
 
-	log.Printf("Saved structured data to %s", jsonPath)
-
 	_ = messaging.SendResponse(models.Response{
 		Status:   "success",
-		Filename: rawPath,
-		JsonFile: jsonPath,
+		Filename: res.RawPath,
+		JsonFile: res.JSONPath,
 	})
 }
 
-func handleAPIRequest(req messaging.APIRequest, database *db.DB) {
+func handleAPIRequest(req messaging.APIRequest, cfg *config.Config, database *db.DB) {
 	if database == nil {
 		_ = messaging.SendAPIResponse(messaging.APIResponse{
 			OK:    false,
@@ -194,7 +156,10 @@ func handleAPIRequest(req messaging.APIRequest, database *db.DB) {
 		})
 
 	case "listJobs":
-		jobs, err := database.ListJobs(100, 0, "")
+		status, _ := req.Data["status"].(string)
+		tag, _ := req.Data["tag"].(string)
+		language, _ := req.Data["language"].(string)
+		jobs, err := database.ListJobs(100, 0, status, tag, language)
 		if err != nil {
 			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
 			return
@@ -215,6 +180,11 @@ func handleAPIRequest(req messaging.APIRequest, database *db.DB) {
 				"status":        j.Status,
 				"extractedAt":   j.ExtractedAt,
 				"url":           j.SourceURL, // original link available in list
+				"tags":          j.Tags,
+				"closedAt":      j.ClosedAt,
+				"language":      j.Language,
+				"suspicious":    j.Suspicious,
+				"needsReview":   j.NeedsReview,
 			})
 		}
 
@@ -236,7 +206,7 @@ func handleAPIRequest(req messaging.APIRequest, database *db.DB) {
 		id := int64(idF)
 
 		// Get full JobPosting + status/notes/rating from DB
-		job, status, notes, rating, err := database.GetJobByID(id)
+		job, rec, err := database.GetJobByID(id)
 		if err != nil {
 			_ = messaging.SendAPIResponse(messaging.APIResponse{
 				OK:    false,
@@ -245,6 +215,67 @@ func handleAPIRequest(req messaging.APIRequest, database *db.DB) {
 			return
 		}
 
+		tags, err := database.GetJobTags(id)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		contacts, err := database.GetJobContacts(id)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		contactsPayload := make([]map[string]any, 0, len(contacts))
+		for _, c := range contacts {
+			contactsPayload = append(contactsPayload, contactPayload(c))
+		}
+
+		interviews, err := database.GetJobInterviews(id)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		interviewsPayload := make([]map[string]any, 0, len(interviews))
+		for _, iv := range interviews {
+			interviewsPayload = append(interviewsPayload, interviewPayload(iv))
+		}
+
+		events, err := database.GetJobEvents(id)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		eventsPayload := make([]map[string]any, 0, len(events))
+		for _, ev := range events {
+			eventsPayload = append(eventsPayload, jobEventPayload(ev))
+		}
+
+		checks, err := database.GetFieldChecks(id)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		checksPayload := make([]map[string]any, 0, len(checks))
+		ungrounded := []map[string]any{}
+		for _, c := range checks {
+			p := fieldCheckPayload(c)
+			checksPayload = append(checksPayload, p)
+			if !c.Grounded {
+				ungrounded = append(ungrounded, p)
+			}
+		}
+
+		spans, err := database.GetEvidence(id)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		evidencePayloads := make([]map[string]any, 0, len(spans))
+		for _, s := range spans {
+			evidencePayloads = append(evidencePayloads, evidencePayload(s))
+		}
+
 		// Flatten technical skills into a single slice
 		var skills []string
 		ts := job.Requirements.TechnicalSkills
@@ -262,10 +293,35 @@ func handleAPIRequest(req messaging.APIRequest, database *db.DB) {
 			"location": job.CompanyInfo.LocationFull,
 			"url":      job.SourceURL, // original link from extracted data
 
-			"status": status,
-			"notes":  notes,
-			"rating": rating,
-			"skills": skills,
+			"status":       rec.Status,
+			"notes":        rec.Notes,
+			"rating":       rec.Rating,
+			"appliedDate":  rec.AppliedDate,
+			"closedAt":     rec.ClosedAt,
+			"closedReason": rec.ClosedReason,
+			"tags":         tags,
+			"contacts":     contactsPayload,
+			"interviews":   interviewsPayload,
+			"events":       eventsPayload,
+			"skills":       skills,
+
+			// Review of the extraction, separate from the pipeline status.
+			"needsReview":   rec.NeedsReview,
+			"reviewReasons": rec.ReviewReasons,
+			"reviewedAt":    rec.ReviewedAt,
+
+			// Set when the posting contained text aimed at the model;
+			// the extracted values deserve a second look.
+			"injectionFlags": job.InjectionFlags,
+
+			// Extracted skills, salaries and experience checked against the
+			// posting text; ungrounded ones may have been made up.
+			"fieldChecks": checksPayload,
+			"ungrounded":  ungrounded,
+
+			// The passages key fields were derived from, with rune offsets
+			// into the stored raw text.
+			"evidence": evidencePayloads,
 
 			// full extracted JSON structure
 			"extracted": job,
@@ -296,90 +352,1010 @@ func handleAPIRequest(req messaging.APIRequest, database *db.DB) {
 				return
 			}
 		}
+		if ratingF, ok := req.Data["rating"].(float64); ok {
+			if ratingF != float64(int(ratingF)) || ratingF < 1 || ratingF > 5 {
+				_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "rating must be an integer from 1 to 5"})
+				return
+			}
+			if err := database.UpdateJobRating(id, int(ratingF)); err != nil {
+				_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+				return
+			}
+		}
+		// An empty appliedDate clears it.
+		if applied, ok := req.Data["appliedDate"].(string); ok {
+			var date *time.Time
+			if applied != "" {
+				t, err := parseDate(applied)
+				if err != nil {
+					_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "invalid appliedDate: " + err.Error()})
+					return
+				}
+				date = &t
+			}
+			if err := database.UpdateJobAppliedDate(id, date); err != nil {
+				_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+				return
+			}
+		}
 
 		_ = messaging.SendAPIResponse(messaging.APIResponse{
 			OK:      true,
 			Payload: map[string]any{"updated": true},
 		})
 
-	case "getAnalytics":
-		statusStats, err := database.GetJobStats()
+	case "editJobFields":
+		idF, ok := req.Data["id"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing id"})
+			return
+		}
+		id := int64(idF)
+
+		patch, ok := req.Data["fields"].(map[string]any)
+		if !ok || len(patch) == 0 {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing fields"})
+			return
+		}
+
+		job, _, err := database.GetJobByID(id)
 		if err != nil {
 			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
 			return
 		}
 
-		// Skill categories we care about
-		categories := []string{
-			"programming_language", // matches your DB
-			"database",
-			"cloud",
-			"devops",
-			"other",
+		// source_url is the job's identity and extracted_at records when the
+		// model ran; neither is editable.
+		sourceURL, extractedAt := job.SourceURL, job.ExtractedAt
+		paths, err := job.ApplyPatch(patch)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
 		}
+		job.SourceURL, job.ExtractedAt = sourceURL, extractedAt
 
-		skillsByCategoryPayload := make(map[string][]map[string]any)
-		for _, cat := range categories {
-			list, err := database.GetTopSkillsByCategory(cat, 15)
-			if err != nil {
-				_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
-				return
+		if errs := job.ValidatePatch(paths); len(errs) > 0 {
+			msgs := make([]string, 0, len(errs))
+			for _, fe := range errs {
+				msgs = append(msgs, fe.Error())
 			}
-			arr := make([]map[string]any, 0, len(list))
-			for _, s := range list {
-				arr = append(arr, map[string]any{
-					"skill": s.SkillName,
-					"count": s.Count,
-				})
-			}
-			skillsByCategoryPayload[cat] = arr
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: strings.Join(msgs, "; ")})
+			return
+		}
+
+		if err := database.UpdateJobFields(id, job); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		// Values the user typed in are confirmed, whether or not the
+		// posting states them.
+		confirmed := &models.JobPosting{}
+		_, _ = confirmed.ApplyPatch(patch)
+		var text string
+		if texts, err := database.ListRawTexts(id); err == nil {
+			text = texts[0].Text
+			confirmed.Absorb(texts[0].Known)
 		}
+		checkExtraction(database, id, job, text, confirmed, false)
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK: true,
+			Payload: map[string]any{
+				"updated":   true,
+				"fields":    paths,
+				"extracted": job,
+			},
+		})
 
-		skillsByStatus, err := database.GetSkillsByStatus(10)
+	case "listReviewQueue":
+		items, err := database.ListReviewQueue()
 		if err != nil {
 			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
 			return
 		}
+		payload := make([]map[string]any, 0, len(items))
+		for _, it := range items {
+			payload = append(payload, map[string]any{
+				"id":          it.JobID,
+				"title":       it.JobTitle,
+				"company":     it.CompanyName,
+				"url":         it.SourceURL,
+				"extractedAt": it.ExtractedAt,
+				"reasons":     it.Reasons,
+			})
+		}
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"jobs": payload},
+		})
 
-		skillsByStatusPayload := make(map[string][]map[string]any)
-		for status, list := range skillsByStatus {
-			arr := make([]map[string]any, 0, len(list))
-			for _, s := range list {
-				arr = append(arr, map[string]any{
-					"skill": s.SkillName,
-					"count": s.Count,
-				})
-			}
-			skillsByStatusPayload[status] = arr
+	case "approveExtraction":
+		idF, ok := req.Data["id"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing id"})
+			return
+		}
+		if err := database.ApproveExtraction(int64(idF)); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
 		}
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"approved": true},
+		})
 
-		titles, err := database.GetTopJobTitles(15)
+	case "listTags":
+		tags, err := database.ListTags()
 		if err != nil {
 			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
 			return
 		}
-		titlesPayload := make([]map[string]any, 0, len(titles))
-		for _, t := range titles {
-			titlesPayload = append(titlesPayload, map[string]any{
-				"title": t.Title,
+
+		tagsPayload := make([]map[string]any, 0, len(tags))
+		for _, t := range tags {
+			tagsPayload = append(tagsPayload, map[string]any{
+				"name":  t.Name,
 				"count": t.Count,
 			})
 		}
 
 		_ = messaging.SendAPIResponse(messaging.APIResponse{
-			OK: true,
-			Payload: map[string]any{
-				"statusStats":      statusStats,
-				"skillsByCategory": skillsByCategoryPayload,
-				"skillsByStatus":   skillsByStatusPayload,
-				"topJobTitles":     titlesPayload,
-			},
+			OK:      true,
+			Payload: map[string]any{"tags": tagsPayload},
 		})
 
-	default:
+	case "addTag", "removeTag":
+		idF, ok := req.Data["id"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing id"})
+			return
+		}
+		id := int64(idF)
+
+		tag, ok := req.Data["tag"].(string)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing tag"})
+			return
+		}
+
+		var err error
+		if req.Action == "addTag" {
+			err = database.AddJobTag(id, tag)
+		} else {
+			err = database.RemoveJobTag(id, tag)
+		}
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		tags, err := database.GetJobTags(id)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
 		_ = messaging.SendAPIResponse(messaging.APIResponse{
-			OK:    false,
-			Error: "unknown action: " + req.Action,
+			OK:      true,
+			Payload: map[string]any{"tags": tags},
+		})
+
+	case "listContacts":
+		search, _ := req.Data["search"].(string)
+		contacts, err := database.ListContacts(search)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		contactsPayload := make([]map[string]any, 0, len(contacts))
+		for _, c := range contacts {
+			contactsPayload = append(contactsPayload, contactPayload(c))
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"contacts": contactsPayload},
+		})
+
+	case "createContact":
+		var c db.Contact
+		applyContactData(&c, req.Data)
+
+		// Optionally link the new contact to a job straight away; the
+		// contact is only created if the link succeeds.
+		jobIDF, _ := req.Data["jobId"].(float64)
+		id, err := database.CreateContact(&c, int64(jobIDF))
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		c.ID = id
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"contact": contactPayload(c)},
+		})
+
+	case "updateContact":
+		idF, ok := req.Data["id"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing id"})
+			return
+		}
+
+		c, err := database.GetContact(int64(idF))
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		applyContactData(c, req.Data)
+
+		if err := database.UpdateContact(c); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"contact": contactPayload(*c)},
+		})
+
+	case "deleteContact":
+		idF, ok := req.Data["id"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing id"})
+			return
+		}
+
+		if err := database.DeleteContact(int64(idF)); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"deleted": true},
 		})
+
+	case "linkContact", "unlinkContact":
+		jobIDF, ok := req.Data["jobId"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing jobId"})
+			return
+		}
+		contactIDF, ok := req.Data["contactId"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing contactId"})
+			return
+		}
+
+		var err error
+		if req.Action == "linkContact" {
+			err = database.LinkContact(int64(jobIDF), int64(contactIDF))
+		} else {
+			err = database.UnlinkContact(int64(jobIDF), int64(contactIDF))
+		}
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"updated": true},
+		})
+
+	case "listInterviews":
+		jobIDF, ok := req.Data["jobId"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing jobId"})
+			return
+		}
+
+		interviews, err := database.GetJobInterviews(int64(jobIDF))
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		interviewsPayload := make([]map[string]any, 0, len(interviews))
+		for _, iv := range interviews {
+			interviewsPayload = append(interviewsPayload, interviewPayload(iv))
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"interviews": interviewsPayload},
+		})
+
+	case "addInterview":
+		jobIDF, ok := req.Data["jobId"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing jobId"})
+			return
+		}
+
+		iv := db.Interview{JobID: int64(jobIDF)}
+		if err := applyInterviewData(&iv, req.Data); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		id, err := database.CreateInterview(&iv)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		iv.ID = id
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"interview": interviewPayload(iv)},
+		})
+
+	case "updateInterview":
+		idF, ok := req.Data["id"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing id"})
+			return
+		}
+
+		iv, err := database.GetInterview(int64(idF))
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		if err := applyInterviewData(iv, req.Data); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		if err := database.UpdateInterview(iv); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"interview": interviewPayload(*iv)},
+		})
+
+	case "deleteInterview":
+		idF, ok := req.Data["id"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing id"})
+			return
+		}
+
+		if err := database.DeleteInterview(int64(idF)); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"deleted": true},
+		})
+
+	case "listJobEvents":
+		jobIDF, ok := req.Data["jobId"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing jobId"})
+			return
+		}
+
+		events, err := database.GetJobEvents(int64(jobIDF))
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		eventsPayload := make([]map[string]any, 0, len(events))
+		for _, ev := range events {
+			eventsPayload = append(eventsPayload, jobEventPayload(ev))
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"events": eventsPayload},
+		})
+
+	case "addJobEvent":
+		jobIDF, ok := req.Data["jobId"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing jobId"})
+			return
+		}
+
+		ev := db.JobEvent{JobID: int64(jobIDF)}
+		if err := applyJobEventData(&ev, req.Data); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		id, err := database.CreateJobEvent(&ev)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		ev.ID = id
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"event": jobEventPayload(ev)},
+		})
+
+	case "updateJobEvent":
+		idF, ok := req.Data["id"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing id"})
+			return
+		}
+
+		ev, err := database.GetJobEvent(int64(idF))
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		if err := applyJobEventData(ev, req.Data); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		if err := database.UpdateJobEvent(ev); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"event": jobEventPayload(*ev)},
+		})
+
+	case "deleteJobEvent":
+		idF, ok := req.Data["id"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing id"})
+			return
+		}
+
+		if err := database.DeleteJobEvent(int64(idF)); err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"deleted": true},
+		})
+
+	case "exportCalendar":
+		// Without a jobId the whole pipeline is exported as one feed.
+		var jobID int64
+		if jobIDF, ok := req.Data["jobId"].(float64); ok {
+			jobID = int64(jobIDF)
+		}
+
+		path, count, err := exportCalendar(cfg, database, jobID)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"file": path, "events": count},
+		})
+
+	case "getReminders":
+		due, err := dueReminders(cfg, database, time.Now())
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		remindersPayload := make([]map[string]any, 0, len(due))
+		for _, r := range due {
+			remindersPayload = append(remindersPayload, reminderPayload(r))
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"reminders": remindersPayload},
+		})
+
+	case "snoozeReminder", "dismissReminder":
+		jobIDF, ok := req.Data["jobId"].(float64)
+		if !ok {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing jobId"})
+			return
+		}
+		rule, ok := req.Data["rule"].(string)
+		if !ok || rule == "" {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing rule"})
+			return
+		}
+
+		now := time.Now()
+		var err error
+		if req.Action == "dismissReminder" {
+			err = database.DismissReminder(int64(jobIDF), rule, now)
+		} else {
+			until := now.AddDate(0, 0, defaultSnoozeDays)
+			if days, ok := req.Data["days"].(float64); ok && days > 0 {
+				until = now.AddDate(0, 0, int(days))
+			}
+			if v, ok := req.Data["until"].(string); ok && v != "" {
+				if until, err = parseDate(v); err != nil {
+					_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "invalid until: " + err.Error()})
+					return
+				}
+			}
+			err = database.SnoozeReminder(int64(jobIDF), rule, until)
+		}
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"updated": true},
+		})
+
+	case "checkLiveness":
+		// Without an id every open job is checked.
+		var id int64
+		if idF, ok := req.Data["id"].(float64); ok {
+			id = int64(idF)
+		}
+		recheckClosed, _ := req.Data["recheckClosed"].(bool)
+
+		checks, err := checkLiveness(context.Background(), database, id, recheckClosed)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		resultsPayload := make([]map[string]any, 0, len(checks))
+		for _, c := range checks {
+			resultsPayload = append(resultsPayload, map[string]any{
+				"id":         c.JobID,
+				"url":        c.URL,
+				"finalUrl":   c.FinalURL,
+				"httpStatus": c.StatusCode,
+				"status":     c.Status,
+				"reason":     c.Reason,
+			})
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"results": resultsPayload},
+		})
+
+	case "extractUrl":
+		rawURL, _ := req.Data["url"].(string)
+		if strings.TrimSpace(rawURL) == "" {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: "missing url"})
+			return
+		}
+		settings, err := settingsFromData(req.Data)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		res, err := extractURL(context.Background(), strings.TrimSpace(rawURL), settings, cfg, database)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error(), Code: errorCode(err)})
+			return
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK: true,
+			Payload: map[string]any{
+				"id":        res.JobID,
+				"title":     res.Job.Metadata.JobTitle,
+				"company":   res.Job.CompanyInfo.CompanyName,
+				"sourceUrl": res.Job.SourceURL,
+				"rawFile":   res.RawPath,
+				"jsonFile":  res.JSONPath,
+			},
+		})
+
+	case "reextract":
+		// Without an id every job is re-extracted.
+		var id int64
+		if idF, ok := req.Data["id"].(float64); ok {
+			id = int64(idF)
+		}
+		dryRun, _ := req.Data["dryRun"].(bool)
+		settings, err := settingsFromData(req.Data)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		results, err := reextract(cfg, database, id, settings, dryRun)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		resultsPayload := make([]map[string]any, 0, len(results))
+		for _, r := range results {
+			item := map[string]any{
+				"id":      r.JobID,
+				"changes": changesPayload(r.Changes),
+			}
+			if r.Err != nil {
+				item["error"] = r.Err.Error()
+				if code := errorCode(r.Err); code != "" {
+					item["code"] = code
+				}
+			}
+			resultsPayload = append(resultsPayload, item)
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:      true,
+			Payload: map[string]any{"results": resultsPayload, "dryRun": dryRun},
+		})
+
+	case "getExtractionStats":
+		months := statsMonths
+		if m, ok := req.Data["months"].(float64); ok && m >= 1 {
+			months = int(m)
+		}
+
+		stats, err := database.GetExtractionStats(statsSince(time.Now(), months))
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		monthsPayload := make([]map[string]any, 0, len(stats.Months))
+		for _, m := range stats.Months {
+			item := runStatsPayload(m.RunStats)
+			item["month"] = m.Month
+			monthsPayload = append(monthsPayload, item)
+		}
+		modelsPayload := make([]map[string]any, 0, len(stats.Models))
+		for _, m := range stats.Models {
+			item := runStatsPayload(m.RunStats)
+			item["provider"] = m.Provider
+			item["model"] = m.Model
+			modelsPayload = append(modelsPayload, item)
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK: true,
+			Payload: map[string]any{
+				"total":  runStatsPayload(stats.Total),
+				"months": monthsPayload,
+				"models": modelsPayload,
+			},
+		})
+
+	case "getAnalytics":
+		// Every chart can be narrowed to one posting language and leaves
+		// out jobs whose extraction awaits review unless asked.
+		var filter db.AnalyticsFilter
+		filter.Language, _ = req.Data["language"].(string)
+		filter.IncludeUnreviewed, _ = req.Data["includeUnreviewed"].(bool)
+
+		unreviewed, err := database.CountUnreviewed()
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		statusStats, err := database.GetJobStats(filter)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		// Skill categories we care about
+		categories := []string{
+			"programming_language", // matches your DB
+			"database",
+			"cloud",
+			"devops",
+			"other",
+		}
+
+		skillsByCategoryPayload := make(map[string][]map[string]any)
+		for _, cat := range categories {
+			list, err := database.GetTopSkillsByCategory(cat, 15, filter)
+			if err != nil {
+				_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+				return
+			}
+			arr := make([]map[string]any, 0, len(list))
+			for _, s := range list {
+				arr = append(arr, map[string]any{
+					"skill": s.SkillName,
+					"count": s.Count,
+				})
+			}
+			skillsByCategoryPayload[cat] = arr
+		}
+
+		skillsByStatus, err := database.GetSkillsByStatus(10, filter)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		skillsByStatusPayload := make(map[string][]map[string]any)
+		for status, list := range skillsByStatus {
+			arr := make([]map[string]any, 0, len(list))
+			for _, s := range list {
+				arr = append(arr, map[string]any{
+					"skill": s.SkillName,
+					"count": s.Count,
+				})
+			}
+			skillsByStatusPayload[status] = arr
+		}
+
+		titles, err := database.GetTopJobTitles(15, filter)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		titlesPayload := make([]map[string]any, 0, len(titles))
+		for _, t := range titles {
+			titlesPayload = append(titlesPayload, map[string]any{
+				"title": t.Title,
+				"count": t.Count,
+			})
+		}
+
+		roundStats, err := database.GetInterviewRoundStats(filter)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		roundJobsPayload := make([]map[string]any, 0, len(roundStats.Jobs))
+		for _, c := range roundStats.Jobs {
+			roundJobsPayload = append(roundJobsPayload, map[string]any{
+				"id":               c.JobID,
+				"title":            c.JobTitle,
+				"company":          c.CompanyName,
+				"status":           c.Status,
+				"promisedRounds":   c.PromisedRounds,
+				"actualRounds":     c.ActualRounds,
+				"promisedTakeHome": c.PromisedTakeHome,
+				"actualTakeHome":   c.ActualTakeHome,
+			})
+		}
+
+		lifetime, err := database.GetPostingLifetimeStats(filter)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+
+		languages, err := database.GetLanguageStats(filter)
+		if err != nil {
+			_ = messaging.SendAPIResponse(messaging.APIResponse{OK: false, Error: err.Error()})
+			return
+		}
+		languagesPayload := make([]map[string]any, 0, len(languages))
+		for _, l := range languages {
+			languagesPayload = append(languagesPayload, map[string]any{
+				"language": l.Language,
+				"name":     langdetect.Name(l.Language),
+				"count":    l.Count,
+			})
+		}
+
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK: true,
+			Payload: map[string]any{
+				"statusStats":      statusStats,
+				"skillsByCategory": skillsByCategoryPayload,
+				"skillsByStatus":   skillsByStatusPayload,
+				"topJobTitles":     titlesPayload,
+				"postingLanguages": languagesPayload,
+				"unreviewedJobs":   unreviewed,
+				"interviewRounds": map[string]any{
+					"jobsWithInterviews":  roundStats.JobsWithInterviews,
+					"jobsWithPromise":     roundStats.JobsWithPromise,
+					"avgPromisedRounds":   roundStats.AvgPromisedRounds,
+					"avgActualRounds":     roundStats.AvgActualRounds,
+					"moreThanPromised":    roundStats.MoreThanPromised,
+					"asPromised":          roundStats.AsPromised,
+					"fewerThanPromised":   roundStats.FewerThanPromised,
+					"unannouncedTakeHome": roundStats.UnannouncedTakeHome,
+					"jobs":                roundJobsPayload,
+				},
+				"postingLifetime": map[string]any{
+					"open":            lifetime.Open,
+					"closed":          lifetime.Closed,
+					"neverChecked":    lifetime.NeverChecked,
+					"avgLifetimeDays": lifetime.AvgLifetimeDays,
+					"minLifetimeDays": lifetime.MinLifetimeDays,
+					"maxLifetimeDays": lifetime.MaxLifetimeDays,
+				},
+			},
+		})
+
+	default:
+		_ = messaging.SendAPIResponse(messaging.APIResponse{
+			OK:    false,
+			Error: "unknown action: " + req.Action,
+		})
+	}
+}
+
+// parseDate accepts a plain date (as sent by <input type="date">) or a full
+// RFC 3339 timestamp.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// applyContactData copies the contact fields present in data onto c, leaving
+// absent fields untouched so updateContact can send partial changes.
+func applyContactData(c *db.Contact, data map[string]any) {
+	fields := map[string]*string{
+		"name":          &c.Name,
+		"role":          &c.Role,
+		"email":         &c.Email,
+		"linkedinUrl":   &c.LinkedInURL,
+		"company":       &c.Company,
+		"notes":         &c.Notes,
+		"lastContacted": &c.LastContacted,
+	}
+	for key, dst := range fields {
+		if v, ok := data[key].(string); ok {
+			*dst = strings.TrimSpace(v)
+		}
+	}
+}
+
+func contactPayload(c db.Contact) map[string]any {
+	return map[string]any{
+		"id":            c.ID,
+		"name":          c.Name,
+		"role":          c.Role,
+		"email":         c.Email,
+		"linkedinUrl":   c.LinkedInURL,
+		"company":       c.Company,
+		"notes":         c.Notes,
+		"lastContacted": c.LastContacted,
+		"updatedAt":     c.UpdatedAt,
+	}
+}
+
+// applyInterviewData copies the interview fields present in data onto iv.
+// interviewers may be sent as an array or a comma-separated string.
+func applyInterviewData(iv *db.Interview, data map[string]any) error {
+	if v, ok := data["roundType"].(string); ok {
+		iv.RoundType = v
+	}
+	if v, ok := data["scheduledAt"].(string); ok {
+		iv.ScheduledAt = ""
+		if v != "" {
+			t, err := parseDateTime(v)
+			if err != nil {
+				return fmt.Errorf("invalid scheduledAt: %w", err)
+			}
+			iv.ScheduledAt = t.UTC().Format(time.RFC3339)
+		}
+	}
+	if v, ok := data["durationMinutes"].(float64); ok {
+		iv.DurationMinutes = int(v)
+	}
+	switch v := data["interviewers"].(type) {
+	case string:
+		iv.Interviewers = nil
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				iv.Interviewers = append(iv.Interviewers, name)
+			}
+		}
+	case []any:
+		iv.Interviewers = nil
+		for _, item := range v {
+			if name, ok := item.(string); ok && strings.TrimSpace(name) != "" {
+				iv.Interviewers = append(iv.Interviewers, strings.TrimSpace(name))
+			}
+		}
+	}
+	if v, ok := data["outcome"].(string); ok {
+		iv.Outcome = v
+	}
+	if v, ok := data["feedback"].(string); ok {
+		iv.Feedback = v
+	}
+	return nil
+}
+
+func interviewPayload(iv db.Interview) map[string]any {
+	return map[string]any{
+		"id":              iv.ID,
+		"jobId":           iv.JobID,
+		"roundType":       iv.RoundType,
+		"scheduledAt":     iv.ScheduledAt,
+		"durationMinutes": iv.DurationMinutes,
+		"interviewers":    iv.Interviewers,
+		"outcome":         iv.Outcome,
+		"feedback":        iv.Feedback,
+		"updatedAt":       iv.UpdatedAt,
+	}
+}
+
+// parseDateTime accepts RFC 3339 or the zone-less value of an
+// <input type="datetime-local">, which is taken as local time.
+func parseDateTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04", s, time.Local)
+}
+
+// applyJobEventData copies the event fields present in data onto ev. A
+// plain date for startsAt makes the event all-day.
+func applyJobEventData(ev *db.JobEvent, data map[string]any) error {
+	if v, ok := data["kind"].(string); ok {
+		ev.Kind = v
+	}
+	if v, ok := data["title"].(string); ok {
+		ev.Title = strings.TrimSpace(v)
+	}
+	if v, ok := data["startsAt"].(string); ok {
+		if t, err := time.Parse("2006-01-02", v); err == nil {
+			ev.StartsAt, ev.AllDay = t, true
+		} else {
+			t, err := parseDateTime(v)
+			if err != nil {
+				return fmt.Errorf("invalid startsAt: %w", err)
+			}
+			ev.StartsAt, ev.AllDay = t, false
+		}
+	}
+	if v, ok := data["endsAt"].(string); ok {
+		ev.EndsAt = time.Time{}
+		if v != "" {
+			t, err := parseDateTime(v)
+			if err != nil {
+				if t, err = time.Parse("2006-01-02", v); err != nil {
+					return fmt.Errorf("invalid endsAt: %w", err)
+				}
+			}
+			ev.EndsAt = t
+		}
+	}
+	if v, ok := data["notes"].(string); ok {
+		ev.Notes = v
+	}
+	return nil
+}
+
+func jobEventPayload(ev db.JobEvent) map[string]any {
+	p := map[string]any{
+		"id":     ev.ID,
+		"jobId":  ev.JobID,
+		"kind":   ev.Kind,
+		"title":  ev.Title,
+		"allDay": ev.AllDay,
+		"notes":  ev.Notes,
+	}
+	if ev.AllDay {
+		p["startsAt"] = ev.StartsAt.UTC().Format("2006-01-02")
+	} else {
+		p["startsAt"] = ev.StartsAt.UTC().Format(time.RFC3339)
+	}
+	if !ev.EndsAt.IsZero() {
+		p["endsAt"] = ev.EndsAt.UTC().Format(time.RFC3339)
 	}
+	return p
 }
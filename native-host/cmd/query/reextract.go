@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+	"native-host/internal/extractor"
+	"native-host/internal/models"
+)
+
+const reextractUsage = "reextract [-provider ollama|perplexity] [-model NAME] [-prompt VERSION] [-no-cache] [-id N] [-dry-run]"
+
+var errNoRawText = errors.New("no raw text stored for this job")
+
+type reextractResult struct {
+	JobID   int64
+	Changes []models.FieldChange
+	Err     error
+}
+
+// reextract runs extraction again on the stored raw text of one job
+// (id > 0) or of every job and, unless dryRun, saves the new fields.
+// Status, notes and everything else tracked outside the extraction are
+// kept.
+func reextract(cfg *config.Config, database *db.DB, id int64, settings models.Settings, dryRun bool) ([]reextractResult, error) {
+	texts, err := database.ListRawTexts(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var legacy map[string]string
+	results := make([]reextractResult, 0, len(texts))
+	for _, rt := range texts {
+		res := reextractResult{JobID: rt.JobID}
+
+		// Jobs saved before the raw text was stored: find the raw file
+		// written next to the structured JSON of the same extraction.
+		backfill := false
+		if rt.Text == "" {
+			if legacy == nil {
+				legacy = legacyRawFiles(cfg.OutputDir)
+			}
+			if path, ok := legacy[rt.SourceURL]; ok {
+				if data, err := os.ReadFile(path); err == nil {
+					rt.Text, rt.Path, backfill = string(data), path, true
+				}
+			}
+		}
+		if rt.Text == "" {
+			res.Err = errNoRawText
+			results = append(results, res)
+			continue
+		}
+
+		res.Changes, res.Err = reextractJob(cfg, database, rt, settings, dryRun)
+		if res.Err == nil && backfill && !dryRun {
+			res.Err = database.SaveRawText(rt.JobID, rt.Text, rt.Path, rt.Known)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func reextractJob(cfg *config.Config, database *db.DB, rt db.RawText, settings models.Settings, dryRun bool) ([]models.FieldChange, error) {
+	old, _, err := database.GetJobByID(rt.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := extractor.Run(rt.Text, settings, rt.Known)
+	recordRun(cfg, database, db.RunReextract, rt.JobID, run, err)
+	if err != nil {
+		return nil, fmt.Errorf("extract with %s: %w", settings.Provider, err)
+	}
+	job := run.Job
+	job.SourceURL = rt.SourceURL
+
+	changes := models.Diff(old, job)
+	if dryRun {
+		return changes, nil
+	}
+	if len(changes) > 0 {
+		if err := database.UpdateJobFields(rt.JobID, job); err != nil {
+			return changes, err
+		}
+	}
+	// An extraction that changed nothing keeps an earlier approval.
+	checkExtraction(database, rt.JobID, job, rt.Text, rt.Known, len(changes) > 0)
+	return changes, nil
+}
+
+// legacyRawFiles maps source URLs to the job_<ts>_raw.txt files written
+// alongside job_<ts>_structured.json, newest extraction winning.
+func legacyRawFiles(dir string) map[string]string {
+	files := map[string]string{}
+	matches, _ := filepath.Glob(filepath.Join(dir, "job_*_structured.json"))
+	for _, path := range matches { // Glob sorts, so timestamps ascend
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var job struct {
+			SourceURL string `json:"source_url"`
+		}
+		if json.Unmarshal(data, &job) != nil || job.SourceURL == "" {
+			continue
+		}
+		raw := strings.TrimSuffix(path, "_structured.json") + "_raw.txt"
+		if _, err := os.Stat(raw); err == nil {
+			files[job.SourceURL] = raw
+		}
+	}
+	return files
+}
+
+func changesPayload(changes []models.FieldChange) []map[string]any {
+	payload := make([]map[string]any, 0, len(changes))
+	for _, c := range changes {
+		payload = append(payload, map[string]any{"field": c.Field, "old": c.Old, "new": c.New})
+	}
+	return payload
+}
+
+func runReextract(args []string, cfg *config.Config, database *db.DB) error {
+	fs := flag.NewFlagSet("reextract", flag.ContinueOnError)
+	settingsFromFlags := providerFlags(fs)
+	id := fs.Int64("id", 0, "re-extract only this job")
+	dryRun := fs.Bool("dry-run", false, "report changes without saving them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	settings, err := settingsFromFlags()
+	if err != nil {
+		return err
+	}
+
+	results, err := reextract(cfg, database, *id, settings, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	changed, failed := 0, 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Printf("job %d: error: %v\n", r.JobID, r.Err)
+		case len(r.Changes) == 0:
+			fmt.Printf("job %d: no changes\n", r.JobID)
+		default:
+			changed++
+			fmt.Printf("job %d: %d fields changed\n", r.JobID, len(r.Changes))
+			for _, c := range r.Changes {
+				fmt.Printf("  %s: %s -> %s\n", c.Field, formatValue(c.Old), formatValue(c.New))
+			}
+		}
+	}
+
+	verb := "updated"
+	if *dryRun {
+		verb = "would change"
+	}
+	fmt.Printf("\n%d jobs: %d %s, %d unchanged, %d failed\n",
+		len(results), changed, verb, len(results)-changed-failed, failed)
+	return nil
+}
+
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+	"native-host/internal/reminders"
+)
+
+// defaultSnoozeDays is used when a snooze request does not say how long.
+const defaultSnoozeDays = 3
+
+const remindersUsage = "reminders [snooze <job-id> <rule> [days] | dismiss <job-id> <rule>]"
+
+// dueReminders evaluates the configured rules against the database.
+func dueReminders(cfg *config.Config, database *db.DB, now time.Time) ([]reminders.Reminder, error) {
+	if err := reminders.ValidateRules(cfg.Reminders); err != nil {
+		return nil, err
+	}
+
+	jobs, err := database.ListReminderCandidates(now)
+	if err != nil {
+		return nil, err
+	}
+	states, err := database.GetReminderStates()
+	if err != nil {
+		return nil, err
+	}
+
+	return reminders.Evaluate(cfg.Reminders, jobs, states, now), nil
+}
+
+func reminderPayload(r reminders.Reminder) map[string]any {
+	return map[string]any{
+		"jobId":       r.JobID,
+		"title":       r.JobTitle,
+		"company":     r.CompanyName,
+		"status":      r.Status,
+		"rule":        r.Rule,
+		"message":     r.Message,
+		"since":       r.Since.UTC().Format(time.RFC3339),
+		"dueAt":       r.DueAt.UTC().Format(time.RFC3339),
+		"daysWaiting": r.DaysWaiting,
+	}
+}
+
+// runReminders lists due follow-ups, or snoozes/dismisses one of them.
+func runReminders(args []string, cfg *config.Config, database *db.DB) error {
+	now := time.Now()
+
+	if len(args) > 0 {
+		if len(args) < 3 {
+			return fmt.Errorf("usage: job-extractor %s", remindersUsage)
+		}
+		jobID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid job id %q", args[1])
+		}
+		rule := args[2]
+
+		switch args[0] {
+		case "snooze":
+			days := defaultSnoozeDays
+			if len(args) > 3 {
+				if days, err = strconv.Atoi(args[3]); err != nil || days <= 0 {
+					return fmt.Errorf("invalid number of days %q", args[3])
+				}
+			}
+			until := now.AddDate(0, 0, days)
+			if err := database.SnoozeReminder(jobID, rule, until); err != nil {
+				return err
+			}
+			fmt.Printf("Snoozed %s for job %d until %s\n", rule, jobID, until.Format("2006-01-02"))
+		case "dismiss":
+			if err := database.DismissReminder(jobID, rule, now); err != nil {
+				return err
+			}
+			fmt.Printf("Dismissed %s for job %d\n", rule, jobID)
+		default:
+			return fmt.Errorf("unknown subcommand %q", args[0])
+		}
+		return nil
+	}
+
+	due, err := dueReminders(cfg, database, now)
+	if err != nil {
+		return err
+	}
+	if len(due) == 0 {
+		fmt.Println("No follow-ups due.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "JOB\tCOMPANY\tTITLE\tSTATUS\tWAITING\tRULE\tMESSAGE")
+	for _, r := range due {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%dd\t%s\t%s\n",
+			r.JobID, r.CompanyName, r.JobTitle, r.Status, r.DaysWaiting, r.Rule, r.Message)
+	}
+	return tw.Flush()
+}
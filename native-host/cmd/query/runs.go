@@ -0,0 +1,63 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+	"native-host/internal/extractor"
+)
+
+// statsMonths is how far back getExtractionStats looks by default.
+const statsMonths = 12
+
+// recordRun stores one extraction attempt with its estimated cost. Cache
+// hits made no request and are not recorded. Failures are only logged:
+// accounting never fails an extraction.
+func recordRun(cfg *config.Config, database *db.DB, kind string, jobID int64, res *extractor.Result, runErr error) {
+	if database == nil || res == nil || res.Cached {
+		return
+	}
+
+	run := db.ExtractionRun{
+		JobID:            jobID,
+		Kind:             kind,
+		Provider:         res.Provider,
+		Model:            res.Model,
+		PromptVersion:    res.PromptVersion,
+		PromptTokens:     res.Usage.PromptTokens,
+		CompletionTokens: res.Usage.CompletionTokens,
+		Latency:          res.Latency,
+	}
+	if price, ok := cfg.Price(res.Provider, res.Model); ok {
+		run.Cost, run.Priced = price.Cost(run.PromptTokens, run.CompletionTokens), true
+	}
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+
+	if err := database.SaveExtractionRun(run); err != nil {
+		log.Printf("Error recording extraction run: %v", err)
+	}
+}
+
+// statsSince returns the first day of the month months-1 months ago, so
+// that months=1 is the current month.
+func statsSince(now time.Time, months int) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func runStatsPayload(s db.RunStats) map[string]any {
+	return map[string]any{
+		"runs":             s.Runs,
+		"failures":         s.Failures,
+		"failureRate":      s.FailureRate(),
+		"promptTokens":     s.PromptTokens,
+		"completionTokens": s.CompletionTokens,
+		"costUsd":          s.Cost,
+		"unpricedRuns":     s.Unpriced,
+		"avgLatencyMs":     s.AvgLatencyMS,
+	}
+}
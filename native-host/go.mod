@@ -3,8 +3,9 @@ module native-host
 go 1.25.1
 
 require (
-	github.com/PuerkitoBio/goquery v1.11.0 // indirect
-	github.com/andybalholm/cascadia v1.3.3 // indirect
-	github.com/mattn/go-sqlite3 v1.14.34 // indirect
-	golang.org/x/net v0.47.0 // indirect
+	github.com/PuerkitoBio/goquery v1.11.0
+	github.com/mattn/go-sqlite3 v1.14.34
+	golang.org/x/net v0.47.0
 )
+
+require github.com/andybalholm/cascadia v1.3.3 // indirect
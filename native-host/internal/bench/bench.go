@@ -0,0 +1,148 @@
+// Package bench measures extraction accuracy against a golden set of
+// postings: raw texts with hand-labeled expected results.
+//
+// A golden set is a directory holding <name>.txt, the posting text as the
+// extension would send it, and <name>.json, the expected models.JobPosting.
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"native-host/internal/models"
+)
+
+// Case is one labeled posting.
+type Case struct {
+	Name     string
+	Text     string
+	Expected *models.JobPosting
+}
+
+// LoadCases reads every <name>.txt / <name>.json pair in dir.
+func LoadCases(dir string) ([]Case, error) {
+	texts, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(texts)
+
+	var cases []Case
+	for _, textPath := range texts {
+		name := strings.TrimSuffix(filepath.Base(textPath), ".txt")
+		text, err := os.ReadFile(textPath)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: expected result: %w", name, err)
+		}
+		var expected models.JobPosting
+		if err := json.Unmarshal(data, &expected); err != nil {
+			return nil, fmt.Errorf("%s: parse expected result: %w", name, err)
+		}
+		cases = append(cases, Case{Name: name, Text: string(text), Expected: &expected})
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no cases in %s", dir)
+	}
+	return cases, nil
+}
+
+// CaseResult is the outcome of extracting one case.
+type CaseResult struct {
+	Name       string
+	Err        error
+	Mismatches []Mismatch
+}
+
+// Report aggregates the per-field counts over all cases. Failed
+// extractions count as false negatives for every expected field.
+type Report struct {
+	Fields map[string]Counts
+	Cases  []CaseResult
+}
+
+// Evaluate runs extract on every case and scores the results.
+func Evaluate(cases []Case, extract func(c Case) (*models.JobPosting, error)) *Report {
+	report := &Report{Fields: map[string]Counts{}}
+	for _, c := range cases {
+		got, err := extract(c)
+		if err != nil {
+			got = &models.JobPosting{}
+		}
+
+		counts, mismatches := Score(c.Expected, got)
+		for path, fc := range counts {
+			total := report.Fields[path]
+			total.add(fc)
+			report.Fields[path] = total
+		}
+		report.Cases = append(report.Cases, CaseResult{Name: c.Name, Err: err, Mismatches: mismatches})
+	}
+	return report
+}
+
+// Overall sums the counts of every field (micro-average).
+func (r *Report) Overall() Counts {
+	var total Counts
+	for _, c := range r.Fields {
+		total.add(c)
+	}
+	return total
+}
+
+// Write prints per-field precision/recall followed by the mismatches of
+// each case.
+func (r *Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tTP\tFP\tFN\tPRECISION\tRECALL\tF1")
+	for _, f := range scoredFields {
+		writeCounts(tw, f.path, r.Fields[f.path])
+	}
+	writeCounts(tw, "overall", r.Overall())
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, c := range r.Cases {
+		switch {
+		case c.Err != nil:
+			fmt.Fprintf(w, "\n%s: extraction failed: %v\n", c.Name, c.Err)
+		case len(c.Mismatches) > 0:
+			fmt.Fprintf(w, "\n%s:\n", c.Name)
+			for _, m := range c.Mismatches {
+				fmt.Fprintf(w, "  %s: expected %s, got %s\n", m.Field, formatValue(m.Expected), formatValue(m.Got))
+			}
+		}
+	}
+	return nil
+}
+
+func writeCounts(w io.Writer, name string, c Counts) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\t%s\n", name, c.TP, c.FP, c.FN,
+		formatRatio(c.Precision()), formatRatio(c.Recall()), formatRatio(c.F1()))
+}
+
+func formatRatio(f float64) string {
+	if math.IsNaN(f) {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", f)
+}
+
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,206 @@
+package bench
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"native-host/internal/extractor"
+	"native-host/internal/models"
+)
+
+const goldenDir = "testdata/golden"
+
+// mockOllama answers /api/generate with the expected result of the case
+// whose text appears in the prompt, passed through mutate when set.
+func mockOllama(t *testing.T, cases []Case, mutate func(name string, job *models.JobPosting)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			http.NotFound(w, r)
+			return
+		}
+		var req struct {
+			Prompt string `json:"prompt"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for _, c := range cases {
+			if !strings.Contains(req.Prompt, strings.TrimSpace(c.Text)) {
+				continue
+			}
+			job := *c.Expected
+			if mutate != nil {
+				mutate(c.Name, &job)
+			}
+			data, _ := json.Marshal(job)
+			json.NewEncoder(w).Encode(map[string]any{
+				"response":          string(data),
+				"done":              true,
+				"prompt_eval_count": len(req.Prompt) / 4,
+				"eval_count":        len(data) / 4,
+			})
+			return
+		}
+		http.Error(w, "no case matches the prompt", http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func loadGolden(t *testing.T) []Case {
+	t.Helper()
+	cases, err := LoadCases(goldenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cases
+}
+
+func extractWith(settings models.Settings) func(Case) (*models.JobPosting, error) {
+	return func(c Case) (*models.JobPosting, error) {
+		return extractor.Extract(c.Text, settings, nil)
+	}
+}
+
+func TestMockPerfect(t *testing.T) {
+	cases := loadGolden(t)
+	srv := mockOllama(t, cases, nil)
+
+	report := Evaluate(cases, extractWith(models.Settings{Provider: "ollama", OllamaURL: srv.URL}))
+	for _, c := range report.Cases {
+		if c.Err != nil || len(c.Mismatches) > 0 {
+			t.Errorf("%s: err=%v mismatches=%+v", c.Name, c.Err, c.Mismatches)
+		}
+	}
+	if o := report.Overall(); o.Precision() != 1 || o.Recall() != 1 {
+		t.Errorf("overall = %+v, want perfect precision and recall", o)
+	}
+}
+
+func TestMockErrors(t *testing.T) {
+	cases := loadGolden(t)
+	srv := mockOllama(t, cases, func(name string, job *models.JobPosting) {
+		if name != "backend-berlin" {
+			return
+		}
+		job.Metadata.SeniorityLevel = "Mid"                                            // wrong: FP and FN
+		job.Compensation.SalaryMin = 76000                                             // within tolerance
+		job.Compensation.HasEquity = false                                             // missed: FN
+		job.Requirements.TechnicalSkills.ProgrammingLanguages = []string{"go", "Rust"} // one hit, one FP, one FN
+	})
+
+	report := Evaluate(cases, extractWith(models.Settings{OllamaURL: srv.URL}))
+
+	// Totals over all three cases; only backend-berlin differs.
+	want := map[string]Counts{
+		"metadata.seniority_level":                            {TP: 2, FP: 1, FN: 1},
+		"compensation.salary_min":                             {TP: 3},
+		"compensation.has_equity":                             {FN: 1},
+		"requirements.technical_skills.programming_languages": {TP: 4, FP: 1, FN: 1},
+	}
+	for path, w := range want {
+		if got := report.Fields[path]; got != w {
+			t.Errorf("%s = %+v, want %+v", path, got, w)
+		}
+	}
+
+	var mismatches []string
+	for _, c := range report.Cases {
+		for _, m := range c.Mismatches {
+			mismatches = append(mismatches, c.Name+":"+m.Field)
+		}
+	}
+	if len(mismatches) != 3 {
+		t.Errorf("mismatches = %v, want seniority, equity and languages of backend-berlin", mismatches)
+	}
+}
+
+func TestExtractionFailure(t *testing.T) {
+	cases := loadGolden(t)[:1]
+	srv := mockOllama(t, nil, nil)
+
+	report := Evaluate(cases, extractWith(models.Settings{OllamaURL: srv.URL}))
+	if report.Cases[0].Err == nil {
+		t.Fatal("expected an extraction error")
+	}
+	if o := report.Overall(); o.TP != 0 || o.FP != 0 || o.FN == 0 {
+		t.Errorf("overall = %+v, want only false negatives", o)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       fieldKind
+		want, have any
+		counts     Counts
+	}{
+		{"text ignores case and spacing", kindText, "Senior  Backend Engineer", "senior backend engineer", Counts{TP: 1}},
+		{"enum is exact", kindEnum, "Full-time", "full-time", Counts{FP: 1, FN: 1}},
+		{"missing value", kindEnum, "Remote", "", Counts{FN: 1}},
+		{"unexpected value", kindNumber, 0, 3, Counts{FP: 1}},
+		{"both empty", kindNumber, 0, 0, Counts{}},
+		{"salary within tolerance", kindSalary, 100000, 104000, Counts{TP: 1}},
+		{"salary outside tolerance", kindSalary, 100000, 110000, Counts{FP: 1, FN: 1}},
+		{"bool", kindBool, true, true, Counts{TP: 1}},
+		{"set overlap", kindSet, []string{"Go", "PostgreSQL", "Redis"}, []string{"go", "Redis", "MySQL", ""}, Counts{TP: 2, FP: 1, FN: 1}},
+		{"empty sets", kindSet, []string(nil), []string{}, Counts{}},
+	}
+	for _, tt := range tests {
+		if got := compare(tt.kind, tt.want, tt.have); got != tt.counts {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.counts)
+		}
+	}
+}
+
+func TestCountsUndefined(t *testing.T) {
+	var c Counts
+	if !math.IsNaN(c.Precision()) || !math.IsNaN(c.Recall()) {
+		t.Errorf("precision/recall of empty counts should be NaN")
+	}
+}
+
+// TestLive runs the golden set against a real provider. It is skipped
+// unless BENCH_PROVIDER is set, e.g.
+//
+//	BENCH_PROVIDER=ollama BENCH_MODEL=qwen2.5:7b go test -run Live -v ./internal/bench
+//
+// Perplexity also needs PERPLEXITY_API_KEY.
+func TestLive(t *testing.T) {
+	provider := os.Getenv("BENCH_PROVIDER")
+	if provider == "" {
+		t.Skip("BENCH_PROVIDER not set")
+	}
+	settings := models.Settings{Provider: provider}
+	switch provider {
+	case "perplexity":
+		settings.PerplexityModel = os.Getenv("BENCH_MODEL")
+		settings.PerplexityKey = os.Getenv("PERPLEXITY_API_KEY")
+	default:
+		settings.OllamaModel = os.Getenv("BENCH_MODEL")
+	}
+
+	dir := os.Getenv("BENCH_DIR")
+	if dir == "" {
+		dir = goldenDir
+	}
+	cases, err := LoadCases(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := Evaluate(cases, extractWith(settings))
+	var out strings.Builder
+	if err := report.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	t.Log("\n" + out.String())
+}
@@ -0,0 +1,194 @@
+package bench
+
+import (
+	"math"
+	"strings"
+
+	"native-host/internal/models"
+)
+
+// How a field's expected and extracted values are compared.
+type fieldKind int
+
+const (
+	kindText   fieldKind = iota // case- and whitespace-insensitive
+	kindEnum                    // exact
+	kindNumber                  // exact
+	kindSalary                  // within salaryTolerance
+	kindBool                    // true is a positive, false means "not stated"
+	kindSet                     // per-element overlap, case-insensitive
+)
+
+// salaryTolerance is the relative error accepted on salaries, which models
+// round ("€80k") or convert slightly differently.
+const salaryTolerance = 0.05
+
+type field struct {
+	path string
+	kind fieldKind
+	get  func(j *models.JobPosting) any
+}
+
+// scoredFields are the fields used for analytics. Free-text fields such as
+// the summary have no single right answer and are not scored.
+var scoredFields = []field{
+	{"metadata.job_title", kindText, func(j *models.JobPosting) any { return j.Metadata.JobTitle }},
+	{"metadata.seniority_level", kindEnum, func(j *models.JobPosting) any { return j.Metadata.SeniorityLevel }},
+	{"metadata.job_function", kindEnum, func(j *models.JobPosting) any { return j.Metadata.JobFunction }},
+	{"company_info.company_name", kindText, func(j *models.JobPosting) any { return j.CompanyInfo.CompanyName }},
+	{"company_info.location_city", kindText, func(j *models.JobPosting) any { return j.CompanyInfo.LocationCity }},
+	{"company_info.location_country", kindText, func(j *models.JobPosting) any { return j.CompanyInfo.LocationCountry }},
+	{"requirements.years_experience_min", kindNumber, func(j *models.JobPosting) any { return j.Requirements.YearsExperienceMin }},
+	{"requirements.years_experience_max", kindNumber, func(j *models.JobPosting) any { return j.Requirements.YearsExperienceMax }},
+	{"requirements.education_level", kindEnum, func(j *models.JobPosting) any { return j.Requirements.EducationLevel }},
+	{"requirements.technical_skills.programming_languages", kindSet, func(j *models.JobPosting) any { return j.Requirements.TechnicalSkills.ProgrammingLanguages }},
+	{"requirements.technical_skills.frameworks", kindSet, func(j *models.JobPosting) any { return j.Requirements.TechnicalSkills.Frameworks }},
+	{"requirements.technical_skills.databases", kindSet, func(j *models.JobPosting) any { return j.Requirements.TechnicalSkills.Databases }},
+	{"requirements.technical_skills.cloud_platforms", kindSet, func(j *models.JobPosting) any { return j.Requirements.TechnicalSkills.CloudPlatforms }},
+	{"requirements.technical_skills.devops_tools", kindSet, func(j *models.JobPosting) any { return j.Requirements.TechnicalSkills.DevOpsTools }},
+	{"compensation.salary_min", kindSalary, func(j *models.JobPosting) any { return j.Compensation.SalaryMin }},
+	{"compensation.salary_max", kindSalary, func(j *models.JobPosting) any { return j.Compensation.SalaryMax }},
+	{"compensation.salary_currency", kindEnum, func(j *models.JobPosting) any { return j.Compensation.SalaryCurrency }},
+	{"compensation.has_equity", kindBool, func(j *models.JobPosting) any { return j.Compensation.HasEquity }},
+	{"compensation.offers_visa_sponsorship", kindBool, func(j *models.JobPosting) any { return j.Compensation.OffersVisa }},
+	{"work_arrangement.workplace_type", kindEnum, func(j *models.JobPosting) any { return j.WorkArrangement.WorkplaceType }},
+	{"work_arrangement.job_type", kindEnum, func(j *models.JobPosting) any { return j.WorkArrangement.JobType }},
+	{"work_arrangement.is_remote_friendly", kindBool, func(j *models.JobPosting) any { return j.WorkArrangement.IsRemoteFriendly }},
+	{"market_signals.has_take_home", kindBool, func(j *models.JobPosting) any { return j.MarketSignals.HasTakeHome }},
+}
+
+// Counts are true positives, false positives and false negatives. A wrong
+// value counts as both a false positive and a false negative.
+type Counts struct {
+	TP, FP, FN int
+}
+
+func (c *Counts) add(o Counts) {
+	c.TP += o.TP
+	c.FP += o.FP
+	c.FN += o.FN
+}
+
+// Precision is NaN when nothing was extracted.
+func (c Counts) Precision() float64 {
+	return ratio(c.TP, c.TP+c.FP)
+}
+
+// Recall is NaN when nothing was expected.
+func (c Counts) Recall() float64 {
+	return ratio(c.TP, c.TP+c.FN)
+}
+
+// F1 is NaN when nothing was expected or extracted.
+func (c Counts) F1() float64 {
+	return ratio(2*c.TP, 2*c.TP+c.FP+c.FN)
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return math.NaN()
+	}
+	return float64(n) / float64(d)
+}
+
+// Mismatch is a scored field whose extracted value isn't the expected one.
+type Mismatch struct {
+	Field    string
+	Expected any
+	Got      any
+}
+
+// Score compares got against the hand-labeled expected posting field by
+// field.
+func Score(expected, got *models.JobPosting) (map[string]Counts, []Mismatch) {
+	counts := make(map[string]Counts, len(scoredFields))
+	var mismatches []Mismatch
+	for _, f := range scoredFields {
+		want, have := f.get(expected), f.get(got)
+		c := compare(f.kind, want, have)
+		counts[f.path] = c
+		if c.FP > 0 || c.FN > 0 {
+			mismatches = append(mismatches, Mismatch{Field: f.path, Expected: want, Got: have})
+		}
+	}
+	return counts, mismatches
+}
+
+func compare(kind fieldKind, want, have any) Counts {
+	if kind == kindSet {
+		return compareSets(want.([]string), have.([]string))
+	}
+
+	wantSet, haveSet := !isEmpty(want), !isEmpty(have)
+	match := wantSet && haveSet && equal(kind, want, have)
+	var c Counts
+	switch {
+	case match:
+		c.TP = 1
+	default:
+		if haveSet {
+			c.FP = 1
+		}
+		if wantSet {
+			c.FN = 1
+		}
+	}
+	return c
+}
+
+func equal(kind fieldKind, want, have any) bool {
+	switch kind {
+	case kindText:
+		return normalize(want.(string)) == normalize(have.(string))
+	case kindSalary:
+		w, h := float64(want.(int)), float64(have.(int))
+		return math.Abs(w-h) <= salaryTolerance*w
+	default:
+		return want == have
+	}
+}
+
+func compareSets(want, have []string) Counts {
+	wantSet, haveSet := stringSet(want), stringSet(have)
+
+	var c Counts
+	for s := range haveSet {
+		if wantSet[s] {
+			c.TP++
+		} else {
+			c.FP++
+		}
+	}
+	for s := range wantSet {
+		if !haveSet[s] {
+			c.FN++
+		}
+	}
+	return c
+}
+
+func stringSet(items []string) map[string]bool {
+	set := map[string]bool{}
+	for _, s := range items {
+		if s = normalize(s); s != "" {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func isEmpty(v any) bool {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t) == ""
+	case int:
+		return t == 0
+	case bool:
+		return !t
+	}
+	return v == nil
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
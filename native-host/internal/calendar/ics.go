@@ -0,0 +1,153 @@
+// Package calendar renders events as iCalendar (RFC 5545) documents.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event is a single VEVENT. UID must stay the same across exports so that
+// calendar clients update the existing entry instead of adding a new one;
+// Sequence must grow whenever the event changes.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time // zero means Start + 1h, or the next day for all-day events
+	AllDay      bool
+	Cancelled   bool
+	Sequence    int64
+	Modified    time.Time
+}
+
+const (
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+// Write renders events into a VCALENDAR named name.
+func Write(w io.Writer, name string, events []Event) error {
+	bw := bufio.NewWriter(w)
+	cw := &contentWriter{w: bw}
+
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//JobFlow//Job Tracker//EN")
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	if name != "" {
+		cw.line("X-WR-CALNAME:" + escapeText(name))
+	}
+
+	now := time.Now().UTC()
+	for _, ev := range events {
+		stamp := ev.Modified
+		if stamp.IsZero() {
+			stamp = now
+		}
+
+		cw.line("BEGIN:VEVENT")
+		cw.line("UID:" + ev.UID)
+		cw.line("DTSTAMP:" + stamp.UTC().Format(dateTimeFormat))
+		cw.line("LAST-MODIFIED:" + stamp.UTC().Format(dateTimeFormat))
+		cw.line(fmt.Sprintf("SEQUENCE:%d", ev.Sequence))
+
+		if ev.AllDay {
+			end := ev.End
+			if end.IsZero() || !end.After(ev.Start) {
+				end = ev.Start.AddDate(0, 0, 1)
+			}
+			cw.line("DTSTART;VALUE=DATE:" + ev.Start.Format(dateFormat))
+			cw.line("DTEND;VALUE=DATE:" + end.Format(dateFormat))
+		} else {
+			end := ev.End
+			if end.IsZero() || !end.After(ev.Start) {
+				end = ev.Start.Add(time.Hour)
+			}
+			cw.line("DTSTART:" + ev.Start.UTC().Format(dateTimeFormat))
+			cw.line("DTEND:" + end.UTC().Format(dateTimeFormat))
+		}
+
+		cw.line("SUMMARY:" + escapeText(ev.Summary))
+		if ev.Description != "" {
+			cw.line("DESCRIPTION:" + escapeText(ev.Description))
+		}
+		if ev.Location != "" {
+			cw.line("LOCATION:" + escapeText(ev.Location))
+		}
+		if ev.URL != "" {
+			cw.line("URL:" + ev.URL)
+		}
+		if ev.Cancelled {
+			cw.line("STATUS:CANCELLED")
+		} else {
+			cw.line("STATUS:CONFIRMED")
+		}
+		cw.line("END:VEVENT")
+	}
+
+	cw.line("END:VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return bw.Flush()
+}
+
+// contentWriter writes folded, CRLF-terminated content lines and keeps the
+// first error.
+type contentWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *contentWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+	_, cw.err = cw.w.WriteString(fold(s) + "\r\n")
+}
+
+// fold splits a content line into chunks of at most 75 octets, continuing
+// each with CRLF and a space. It never cuts a UTF-8 sequence in half.
+func fold(s string) string {
+	if len(s) <= maxLineOctets {
+		return s
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines lose one octet to the leading space.
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// escapeText escapes a TEXT property value per RFC 5545 section 3.3.11.
+func escapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return r.Replace(s)
+}
@@ -0,0 +1,84 @@
+package calendar
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"native-host/internal/golden"
+)
+
+// TestWriteGolden renders a feed with long, escaped and non-ASCII values and
+// compares it with testdata/feed.golden.ics. After changing the output, run
+// `go test ./internal/calendar -update` and review the golden diff.
+func TestWriteGolden(t *testing.T) {
+	modified := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	events := []Event{
+		{
+			UID:         "interview-7@jobflow.local",
+			Summary:     "Interview (system design): Senior Go Engineer @ Acme, Inc.",
+			Description: "Interviewers: Doe, Jane; Max Mustermann\n\nBring notes; C:\\temp is fine\n\nhttps://acme.example/jobs/1234567890/senior-go-engineer-platform-infrastructure",
+			URL:         "https://acme.example/jobs/1234567890/senior-go-engineer-platform-infrastructure",
+			Start:       time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC),
+			End:         time.Date(2026, 11, 2, 11, 30, 0, 0, time.UTC),
+			Sequence:    2,
+			Modified:    modified,
+		},
+		{
+			UID:       "event-3@jobflow.local",
+			Summary:   "Bewerbungsfrist: Softwareentwicklerin für Zahlungsverkehr @ Müller & Söhne GmbH — München",
+			Start:     time.Date(2026, 11, 15, 0, 0, 0, 0, time.UTC),
+			AllDay:    true,
+			Cancelled: true,
+			Modified:  modified,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "JobFlow: Acme, Inc.", events); err != nil {
+		t.Fatal(err)
+	}
+	golden.Compare(t, filepath.Join("testdata", "feed.golden.ics"), buf.Bytes())
+}
+
+func TestFold(t *testing.T) {
+	for _, s := range []string{
+		"SUMMARY:short",
+		"DESCRIPTION:" + strings.Repeat("x", 200),
+		"SUMMARY:" + strings.Repeat("ü", 80),
+		"SUMMARY:" + strings.Repeat("a€", 60),
+	} {
+		folded := fold(s)
+		lines := strings.Split(folded, "\r\n")
+		for i, line := range lines {
+			if len(line) > maxLineOctets {
+				t.Errorf("line %d has %d octets: %q", i, len(line), line)
+			}
+			if i > 0 && !strings.HasPrefix(line, " ") {
+				t.Errorf("continuation line %d does not start with a space: %q", i, line)
+			}
+			if !utf8.ValidString(line) {
+				t.Errorf("line %d cuts a UTF-8 sequence: %q", i, line)
+			}
+		}
+		if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != s {
+			t.Errorf("unfolding gives %q, want %q", unfolded, s)
+		}
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"plain", "plain"},
+		{"Doe, Jane; Max", `Doe\, Jane\; Max`},
+		{`C:\temp`, `C:\\temp`},
+		{"one\r\ntwo\nthree\rfour", `one\ntwo\nthree\nfour`},
+	} {
+		if got := escapeText(tc.in); got != tc.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
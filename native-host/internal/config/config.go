@@ -1,16 +1,141 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 )
 
 type Config struct {
-	HomeDir    string
-	OutputDir  string
-	DBPath     string
-	LogPath    string
-	SchemaPath string
+	HomeDir      string `json:"-"`
+	OutputDir    string `json:"-"`
+	DBPath       string `json:"-"`
+	LogPath      string `json:"-"`
+	SchemaPath   string `json:"-"`
+	SettingsPath string `json:"-"`
+
+	// SettingsErr is why SettingsPath could not be loaded, if it could
+	// not. The defaults are used instead, so a typo in the file doesn't
+	// stop the host.
+	SettingsErr error `json:"-"`
+
+	// User-tunable settings, overridable in SettingsPath.
+	Reminders []ReminderRule `json:"reminders"`
+
+	// PromptDir holds prompt template overrides, one directory per
+	// version (see the extractor package).
+	PromptDir string `json:"promptDir"`
+
+	// MaxPostingChars is how much posting text goes into one prompt;
+	// longer postings are extracted in chunks.
+	MaxPostingChars int `json:"maxPostingChars"`
+
+	// RedactTerms are personal strings, e.g. your name, removed from the
+	// text sent to remote providers. Emails and phone numbers always are.
+	RedactTerms []string `json:"redactTerms"`
+
+	// Prices estimate what each extraction costs.
+	Prices []ModelPrice `json:"prices"`
+
+	// Budgets cap spending and request rates per provider, e.g.
+	// {"perplexity": {"monthly": 10, "requestsPerMinute": 20}}.
+	Budgets map[string]Budget `json:"budgets"`
+
+	// BudgetFallbackModel is the local Ollama model used instead of a
+	// provider over budget. Empty refuses with a budget_exceeded error.
+	BudgetFallbackModel string `json:"budgetFallbackModel"`
+}
+
+// Budget limits one provider. Amounts are in USD, estimated from Prices;
+// zero fields are unlimited. A provider with a daily or monthly limit
+// only runs models that have a price, model-specific or provider-wide.
+type Budget struct {
+	Daily             float64 `json:"daily"`
+	Monthly           float64 `json:"monthly"`
+	RequestsPerMinute int     `json:"requestsPerMinute"`
+}
+
+// ModelPrice is what a provider charges per million tokens, in USD. An
+// empty Model applies to the provider's models that have no entry of
+// their own.
+type ModelPrice struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	InputPerMillion  float64 `json:"inputPerMillion"`
+	OutputPerMillion float64 `json:"outputPerMillion"`
+}
+
+// Cost estimates the price of one call. Per-request fees, such as
+// Perplexity's search fees, are not included.
+func (p ModelPrice) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.InputPerMillion + float64(completionTokens)*p.OutputPerMillion) / 1e6
+}
+
+// DefaultPrices are used when the settings file has no prices. Local
+// Ollama models cost nothing.
+var DefaultPrices = []ModelPrice{
+	{Provider: "ollama"},
+	{Provider: "perplexity", Model: "sonar", InputPerMillion: 1, OutputPerMillion: 1},
+	{Provider: "perplexity", Model: "sonar-pro", InputPerMillion: 3, OutputPerMillion: 15},
+	{Provider: "perplexity", Model: "sonar-reasoning", InputPerMillion: 1, OutputPerMillion: 5},
+	{Provider: "perplexity", Model: "sonar-reasoning-pro", InputPerMillion: 2, OutputPerMillion: 8},
+}
+
+// Price returns the price of a model, falling back to the provider-wide
+// entry. ok is false when neither is configured.
+func (c *Config) Price(provider, model string) (price ModelPrice, ok bool) {
+	for _, p := range c.Prices {
+		if p.Provider != provider {
+			continue
+		}
+		if p.Model == model {
+			return p, true
+		}
+		if p.Model == "" {
+			price, ok = p, true
+		}
+	}
+	return price, ok
+}
+
+// DefaultMaxPostingChars leaves room for the instructions and the answer
+// in a 4096-token context, the smallest common among local models.
+const DefaultMaxPostingChars = 8000
+
+// ReminderRule flags jobs that have sat in Status for more than Days,
+// counted from Since: "updated_at" (the last status change, not edits to
+// notes or rating), "applied_date" (falling back to the last status change)
+// or "last_interview" (latest past interview still pending).
+type ReminderRule struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Since   string `json:"since"`
+	Days    int    `json:"days"`
+	Message string `json:"message"`
+}
+
+// ReminderAnchors are the accepted values for ReminderRule.Since.
+var ReminderAnchors = []string{"updated_at", "applied_date", "last_interview"}
+
+// DefaultReminders are used when the settings file has no reminders.
+var DefaultReminders = []ReminderRule{
+	{
+		Name:    "applied-no-response",
+		Status:  "applied",
+		Since:   "applied_date",
+		Days:    7,
+		Message: "Applied over a week ago with no status change; follow up with the recruiter.",
+	},
+	{
+		Name:    "interview-no-outcome",
+		Status:  "interview",
+		Since:   "last_interview",
+		Days:    3,
+		Message: "Interviewed over 3 days ago with no outcome; ask for feedback.",
+	},
 }
 
 func Load() (*Config, error) {
@@ -22,16 +147,49 @@ func Load() (*Config, error) {
 	outputDir := filepath.Join(homeDir, "Downloads", "extracted_jobs")
 
 	cfg := &Config{
-		HomeDir:    homeDir,
-		OutputDir:  outputDir,
-		DBPath:     filepath.Join(outputDir, "jobs.db"),
-		LogPath:    filepath.Join(homeDir, "Downloads", "extractor.log"),
-		SchemaPath: filepath.Join(homeDir, "Projects", "text-extractor", "native-host", "schema.sql"),
+		HomeDir:      homeDir,
+		OutputDir:    outputDir,
+		DBPath:       filepath.Join(outputDir, "jobs.db"),
+		LogPath:      filepath.Join(homeDir, "Downloads", "extractor.log"),
+		SchemaPath:   filepath.Join(homeDir, "Projects", "text-extractor", "native-host", "schema.sql"),
+		SettingsPath: filepath.Join(outputDir, "config.json"),
+		PromptDir:    filepath.Join(outputDir, "prompts"),
+	}
+
+	if err := cfg.loadSettings(); err != nil {
+		cfg.SettingsErr = err
+	}
+	if len(cfg.Reminders) == 0 {
+		cfg.Reminders = DefaultReminders
+	}
+	if len(cfg.Prices) == 0 {
+		cfg.Prices = DefaultPrices
+	}
+	if cfg.MaxPostingChars == 0 {
+		cfg.MaxPostingChars = DefaultMaxPostingChars
 	}
 
 	return cfg, nil
 }
 
+// loadSettings overlays the optional JSON settings file onto cfg. On error
+// cfg is left unchanged.
+func (c *Config) loadSettings() error {
+	data, err := os.ReadFile(c.SettingsPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read settings: %w", err)
+	}
+	overlay := *c
+	if err := json.Unmarshal(data, &overlay); err != nil {
+		return fmt.Errorf("parse %s: %w", c.SettingsPath, err)
+	}
+	*c = overlay
+	return nil
+}
+
 func (c *Config) EnsureDirectories() error {
 	return os.MkdirAll(c.OutputDir, 0755)
 }
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"native-host/internal/models"
+)
+
+// GetCachedExtraction returns the cached posting for key, or nil on a miss.
+func (db *DB) GetCachedExtraction(key string) (*models.JobPosting, error) {
+	var data string
+	err := db.QueryRow(`SELECT job_json FROM extraction_cache WHERE key = ?`, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var job models.JobPosting
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`UPDATE extraction_cache SET hits = hits + 1, last_hit_at = CURRENT_TIMESTAMP WHERE key = ?`, key)
+	return &job, err
+}
+
+// PutCachedExtraction stores job under key, replacing any earlier entry.
+func (db *DB) PutCachedExtraction(key, provider, model, promptVersion string, job *models.JobPosting) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	query := `
+        INSERT INTO extraction_cache (key, provider, model, prompt_version, job_json)
+        VALUES (?, ?, ?, ?, ?)
+        ON CONFLICT(key) DO UPDATE SET
+            job_json = excluded.job_json,
+            hits = 0,
+            created_at = CURRENT_TIMESTAMP,
+            last_hit_at = NULL
+    `
+	_, err = db.Exec(query, key, provider, model, promptVersion, string(data))
+	return err
+}
+
+// CacheFilter selects cache entries; zero fields match everything.
+type CacheFilter struct {
+	Provider      string
+	Model         string
+	PromptVersion string
+	UnusedSince   time.Time // entries neither created nor hit since then
+}
+
+// EvictCache deletes the matching cache entries and returns how many.
+func (db *DB) EvictCache(f CacheFilter) (int64, error) {
+	var unusedSince any
+	if !f.UnusedSince.IsZero() {
+		unusedSince = f.UnusedSince.UTC()
+	}
+	query := `
+        DELETE FROM extraction_cache
+        WHERE (? = '' OR provider = ?)
+          AND (? = '' OR model = ?)
+          AND (? = '' OR prompt_version = ?)
+          AND (? IS NULL OR COALESCE(last_hit_at, created_at) < ?)
+    `
+	res, err := db.Exec(query, f.Provider, f.Provider, f.Model, f.Model,
+		f.PromptVersion, f.PromptVersion, unusedSince, unusedSince)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// CacheStats summarizes the cache per provider, model and prompt version.
+type CacheStats struct {
+	Provider      string
+	Model         string
+	PromptVersion string
+	Entries       int
+	Hits          int
+}
+
+func (db *DB) GetCacheStats() ([]CacheStats, error) {
+	query := `
+        SELECT provider, model, prompt_version, COUNT(*), IFNULL(SUM(hits), 0)
+        FROM extraction_cache
+        GROUP BY provider, model, prompt_version
+        ORDER BY provider, model, prompt_version
+    `
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []CacheStats
+	for rows.Next() {
+		var s CacheStats
+		if err := rows.Scan(&s.Provider, &s.Model, &s.PromptVersion, &s.Entries, &s.Hits); err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
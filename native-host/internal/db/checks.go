@@ -0,0 +1,54 @@
+package db
+
+import (
+	"fmt"
+
+	"native-host/internal/grounding"
+)
+
+// SaveFieldChecks replaces the grounding checks stored for a job.
+func (db *DB) SaveFieldChecks(jobID int64, checks []grounding.FieldCheck) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM job_field_checks WHERE job_id = ?`, jobID); err != nil {
+		return fmt.Errorf("clear field checks: %w", err)
+	}
+	for _, c := range checks {
+		if _, err := tx.Exec(`
+            INSERT INTO job_field_checks (job_id, field, value, grounded, confidence)
+            VALUES (?, ?, ?, ?, ?)
+            ON CONFLICT(job_id, field, value) DO NOTHING
+        `, jobID, c.Field, c.Value, c.Grounded, c.Confidence); err != nil {
+			return fmt.Errorf("insert field check: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetFieldChecks returns a job's grounding checks, ungrounded ones first.
+func (db *DB) GetFieldChecks(jobID int64) ([]grounding.FieldCheck, error) {
+	rows, err := db.Query(`
+        SELECT field, value, grounded, confidence
+        FROM job_field_checks
+        WHERE job_id = ?
+        ORDER BY grounded, field, value
+    `, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checks := []grounding.FieldCheck{}
+	for rows.Next() {
+		var c grounding.FieldCheck
+		if err := rows.Scan(&c.Field, &c.Value, &c.Grounded, &c.Confidence); err != nil {
+			return nil, err
+		}
+		checks = append(checks, c)
+	}
+	return checks, rows.Err()
+}
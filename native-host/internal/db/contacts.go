@@ -0,0 +1,257 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"native-host/internal/models"
+)
+
+// ContactRoles are the accepted values for Contact.Role. Empty is allowed.
+var ContactRoles = []string{"recruiter", "hiring_manager", "referrer", "interviewer", "other"}
+
+// Contact is a person we deal with during one or more applications.
+type Contact struct {
+	ID            int64
+	Name          string
+	Role          string
+	Email         string
+	LinkedInURL   string
+	Company       string
+	Notes         string
+	LastContacted string // YYYY-MM-DD, empty when never contacted
+	UpdatedAt     string
+}
+
+// Validate checks the fields a user can get wrong when typing a contact in.
+func (c *Contact) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("contact name is required")
+	}
+	if c.Role != "" && !models.Contains(ContactRoles, c.Role) {
+		return fmt.Errorf("role %q is not one of %s", c.Role, strings.Join(ContactRoles, ", "))
+	}
+	if c.Email != "" {
+		if _, err := mail.ParseAddress(c.Email); err != nil {
+			return fmt.Errorf("invalid email: %w", err)
+		}
+	}
+	if c.LinkedInURL != "" {
+		u, err := url.Parse(c.LinkedInURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid LinkedIn URL %q", c.LinkedInURL)
+		}
+	}
+	if c.LastContacted != "" {
+		if _, err := time.Parse("2006-01-02", c.LastContacted); err != nil {
+			return fmt.Errorf("invalid last contacted date: %w", err)
+		}
+	}
+	return nil
+}
+
+const contactColumns = `c.id, c.name, c.role, c.email, c.linkedin_url, c.company, c.notes, c.last_contacted, c.updated_at`
+
+func scanContact(row interface{ Scan(...any) error }) (Contact, error) {
+	var c Contact
+	var role, email, linkedIn, company, notes sql.NullString
+	var lastContacted sql.NullTime
+	var updatedAt sql.NullString
+
+	if err := row.Scan(&c.ID, &c.Name, &role, &email, &linkedIn, &company, &notes, &lastContacted, &updatedAt); err != nil {
+		return c, err
+	}
+
+	c.Role = role.String
+	c.Email = email.String
+	c.LinkedInURL = linkedIn.String
+	c.Company = company.String
+	c.Notes = notes.String
+	c.UpdatedAt = updatedAt.String
+	if lastContacted.Valid {
+		c.LastContacted = lastContacted.Time.Format("2006-01-02")
+	}
+	return c, nil
+}
+
+// lastContactedValue converts the YYYY-MM-DD string to a value for the
+// last_contacted column, NULL when empty.
+func lastContactedValue(s string) (any, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// ListContacts returns all contacts. A non-empty search matches name,
+// company or email.
+func (db *DB) ListContacts(search string) ([]Contact, error) {
+	query := `
+        SELECT ` + contactColumns + `
+        FROM contacts c
+        WHERE (? = '' OR c.name LIKE ? OR c.company LIKE ? OR c.email LIKE ?)
+        ORDER BY c.name COLLATE NOCASE
+    `
+	like := "%" + search + "%"
+	rows, err := db.Query(query, search, like, like, like)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := []Contact{}
+	for rows.Next() {
+		c, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
+
+func (db *DB) GetContact(id int64) (*Contact, error) {
+	row := db.QueryRow(`SELECT `+contactColumns+` FROM contacts c WHERE c.id = ?`, id)
+	c, err := scanContact(row)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// CreateContact inserts c and, when jobID > 0, links it to that job in the
+// same transaction, so a failed link leaves no contact behind.
+func (db *DB) CreateContact(c *Contact, jobID int64) (int64, error) {
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+	lastContacted, err := lastContactedValue(c.LastContacted)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO contacts (name, role, email, linkedin_url, company, notes, last_contacted)
+        VALUES (?, ?, ?, ?, ?, ?, ?)
+    `
+	result, err := tx.Exec(query, c.Name, c.Role, c.Email, c.LinkedInURL, c.Company, c.Notes, lastContacted)
+	if err != nil {
+		return 0, fmt.Errorf("insert contact: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if jobID > 0 {
+		result, err := tx.Exec(`INSERT INTO job_contacts (job_id, contact_id) SELECT id, ? FROM jobs WHERE id = ?`, id, jobID)
+		if err != nil {
+			return 0, fmt.Errorf("link contact: %w", err)
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return 0, fmt.Errorf("job %d not found", jobID)
+		}
+	}
+	return id, tx.Commit()
+}
+
+func (db *DB) UpdateContact(c *Contact) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	lastContacted, err := lastContactedValue(c.LastContacted)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        UPDATE contacts SET
+            name = ?, role = ?, email = ?, linkedin_url = ?, company = ?, notes = ?,
+            last_contacted = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	result, err := db.Exec(query, c.Name, c.Role, c.Email, c.LinkedInURL, c.Company, c.Notes, lastContacted, c.ID)
+	if err != nil {
+		return fmt.Errorf("update contact: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (db *DB) DeleteContact(id int64) error {
+	_, err := db.Exec(`DELETE FROM job_contacts WHERE contact_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`DELETE FROM contacts WHERE id = ?`, id)
+	return err
+}
+
+// LinkContact attaches a contact to a job. Linking twice is a no-op.
+func (db *DB) LinkContact(jobID, contactID int64) error {
+	query := `
+        INSERT INTO job_contacts (job_id, contact_id)
+        SELECT j.id, c.id FROM jobs j, contacts c WHERE j.id = ? AND c.id = ?
+        ON CONFLICT DO NOTHING
+    `
+	result, err := db.Exec(query, jobID, contactID)
+	if err != nil {
+		return fmt.Errorf("link contact: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		var exists bool
+		if err := db.QueryRow(
+			`SELECT EXISTS(SELECT 1 FROM job_contacts WHERE job_id = ? AND contact_id = ?)`,
+			jobID, contactID,
+		).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("job %d or contact %d not found", jobID, contactID)
+		}
+	}
+	return nil
+}
+
+func (db *DB) UnlinkContact(jobID, contactID int64) error {
+	_, err := db.Exec(`DELETE FROM job_contacts WHERE job_id = ? AND contact_id = ?`, jobID, contactID)
+	return err
+}
+
+// GetJobContacts returns the contacts linked to a job.
+func (db *DB) GetJobContacts(jobID int64) ([]Contact, error) {
+	query := `
+        SELECT ` + contactColumns + `
+        FROM job_contacts jc
+        JOIN contacts c ON c.id = jc.contact_id
+        WHERE jc.job_id = ?
+        ORDER BY c.name COLLATE NOCASE
+    `
+	rows, err := db.Query(query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := []Contact{}
+	for rows.Next() {
+		c, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
@@ -13,7 +13,10 @@ type DB struct {
 }
 
 func Init(dbPath string) (*DB, error) {
-	sqlDB, err := sql.Open("sqlite3", dbPath)
+	// Writers wait for each other instead of failing with "database is
+	// locked": the extension can start several hosts at once and the import
+	// command saves from several workers.
+	sqlDB, err := sql.Open("sqlite3", dbPath+"?_busy_timeout=5000")
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -22,9 +25,52 @@ func Init(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("execute schema: %w", err)
 	}
 
+	if err := migrate(sqlDB); err != nil {
+		return nil, fmt.Errorf("migrate schema: %w", err)
+	}
+
 	return &DB{sqlDB}, nil
 }
 
+// migrate adds the columns in columnMigrations that an existing database
+// created from an older Schema is missing.
+func migrate(sqlDB *sql.DB) error {
+	existing := make(map[string]map[string]bool)
+	for _, m := range columnMigrations {
+		cols, ok := existing[m.table]
+		if !ok {
+			rows, err := sqlDB.Query(`SELECT name FROM pragma_table_info(?)`, m.table)
+			if err != nil {
+				return err
+			}
+			cols = make(map[string]bool)
+			for rows.Next() {
+				var name string
+				if err := rows.Scan(&name); err != nil {
+					rows.Close()
+					return err
+				}
+				cols[name] = true
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			existing[m.table] = cols
+		}
+
+		if cols[m.column] {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.definition)
+		if _, err := sqlDB.Exec(stmt); err != nil {
+			return fmt.Errorf("add %s.%s: %w", m.table, m.column, err)
+		}
+		cols[m.column] = true
+	}
+	return nil
+}
+
 func (db *DB) SaveJob(job *models.JobPosting) (int64, error) {
 	rawJSON, err := json.Marshal(job)
 	if err != nil {
@@ -51,7 +97,7 @@ func (db *DB) SaveJob(job *models.JobPosting) (int64, error) {
             offers_professional_development, offers_401k,
             urgency_level, interview_rounds, has_take_home, has_pair_programming,
             summary, key_responsibilities, team_structure, benefits, soft_skills, nice_to_have,
-            status, raw_json
+            prompt_version, posting_language, injection_flags, status, status_changed_at, raw_json
         ) VALUES (
             ?, ?,                             -- 1-2
             ?, ?, ?, ?,                       -- 3-6
@@ -63,7 +109,9 @@ func (db *DB) SaveJob(job *models.JobPosting) (int64, error) {
             ?, ?, ?, ?, ?,                    -- 26-30
             ?, ?, ?, ?,                       -- 31-34
             ?, ?, ?, ?, ?, ?,                 -- 35-40
-            'saved', ?                        -- status literal, raw_json last
+            NULLIF(?, ''), NULLIF(?, ''),     -- prompt_version, posting_language
+            NULLIF(?, ''),                    -- injection_flags
+            'saved', CURRENT_TIMESTAMP, ?     -- status literal, raw_json last
         )
         ON CONFLICT(source_url) DO UPDATE SET
             updated_at = CURRENT_TIMESTAMP,
@@ -74,7 +122,11 @@ func (db *DB) SaveJob(job *models.JobPosting) (int64, error) {
             salary_min = excluded.salary_min,
             salary_max = excluded.salary_max,
             is_remote_friendly = excluded.is_remote_friendly,
+            prompt_version = excluded.prompt_version,
+            posting_language = excluded.posting_language,
+            injection_flags = excluded.injection_flags,
             raw_json = excluded.raw_json
+        RETURNING id
     `
 
 	tx, err := db.Begin()
@@ -83,7 +135,10 @@ func (db *DB) SaveJob(job *models.JobPosting) (int64, error) {
 	}
 	defer tx.Rollback()
 
-	result, err := tx.Exec(query,
+	// RETURNING rather than LastInsertId, which is not the job's id when
+	// the upsert updated an existing row.
+	var jobID int64
+	err = tx.QueryRow(query,
 		// 1-2
 		job.SourceURL, job.ExtractedAt,
 
@@ -143,18 +198,16 @@ func (db *DB) SaveJob(job *models.JobPosting) (int64, error) {
 		softSkills,
 		niceToHave,
 
-		// raw_json (last)
+		// prompt_version, posting_language, injection_flags, raw_json (last)
+		job.PromptVersion,
+		job.PostingLanguage,
+		strings.Join(job.InjectionFlags, ","),
 		string(rawJSON),
-	)
+	).Scan(&jobID)
 	if err != nil {
 		return 0, fmt.Errorf("insert job: %w", err)
 	}
 
-	jobID, err := result.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("get last insert id: %w", err)
-	}
-
 	if err := db.saveSkills(tx, jobID, job.Requirements.TechnicalSkills); err != nil {
 		return 0, fmt.Errorf("save skills: %w", err)
 	}
@@ -208,3 +261,114 @@ func (db *DB) saveSkills(tx *sql.Tx, jobID int64, skills models.TechnicalSkills)
 
 	return nil
 }
+
+// UpdateJobFields overwrites the extracted data of an existing job: the
+// flattened columns, raw_json and the job_skills rows. It is used when a job
+// is corrected by hand or re-extracted, so unlike SaveJob it touches every
+// column.
+func (db *DB) UpdateJobFields(id int64, job *models.JobPosting) error {
+	rawJSON, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("marshal job: %w", err)
+	}
+
+	query := `
+        UPDATE jobs SET
+            job_title = ?, company_name = ?, company_size = ?, industry = ?,
+            location_full = ?, location_city = ?, location_country = ?,
+            seniority_level = ?, department = ?, job_function = ?,
+            workplace_type = ?, job_type = ?, is_remote_friendly = ?, timezone_requirements = ?,
+            years_experience_min = ?, years_experience_max = ?, education_level = ?, requires_specific_degree = ?,
+            salary_min = ?, salary_max = ?, salary_currency = ?, has_equity = ?, has_remote_stipend = ?,
+            offers_visa_sponsorship = ?, offers_health_insurance = ?, offers_pto = ?,
+            offers_professional_development = ?, offers_401k = ?,
+            urgency_level = ?, interview_rounds = ?, has_take_home = ?, has_pair_programming = ?,
+            summary = ?, key_responsibilities = ?, team_structure = ?, benefits = ?, soft_skills = ?, nice_to_have = ?,
+            prompt_version = NULLIF(?, ''), posting_language = NULLIF(?, ''),
+            injection_flags = NULLIF(?, ''), raw_json = ?,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query,
+		job.Metadata.JobTitle,
+		job.CompanyInfo.CompanyName,
+		job.CompanyInfo.CompanySize,
+		job.CompanyInfo.Industry,
+
+		job.CompanyInfo.LocationFull,
+		job.CompanyInfo.LocationCity,
+		job.CompanyInfo.LocationCountry,
+
+		job.Metadata.SeniorityLevel,
+		job.Metadata.Department,
+		job.Metadata.JobFunction,
+
+		job.WorkArrangement.WorkplaceType,
+		job.WorkArrangement.JobType,
+		job.WorkArrangement.IsRemoteFriendly,
+		job.WorkArrangement.TimezoneRequirements,
+
+		job.Requirements.YearsExperienceMin,
+		job.Requirements.YearsExperienceMax,
+		job.Requirements.EducationLevel,
+		job.Requirements.RequiresSpecificDegree,
+
+		job.Compensation.SalaryMin,
+		job.Compensation.SalaryMax,
+		job.Compensation.SalaryCurrency,
+		job.Compensation.HasEquity,
+		job.Compensation.HasRemoteStipend,
+
+		job.Compensation.OffersVisa,
+		job.Compensation.OffersHealthInsurance,
+		job.Compensation.OffersPTO,
+		job.Compensation.OffersProfDev,
+		job.Compensation.Offers401k,
+
+		job.MarketSignals.UrgencyLevel,
+		job.MarketSignals.InterviewRounds,
+		job.MarketSignals.HasTakeHome,
+		job.MarketSignals.HasPairProgramming,
+
+		job.RoleDetails.Summary,
+		strings.Join(job.RoleDetails.KeyResponsibilities, "\n• "),
+		job.RoleDetails.TeamStructure,
+		strings.Join(job.Compensation.Benefits, ", "),
+		strings.Join(job.Requirements.SoftSkills, ", "),
+		strings.Join(job.Requirements.NiceToHave, "; "),
+
+		job.PromptVersion,
+		job.PostingLanguage,
+		strings.Join(job.InjectionFlags, ","),
+		string(rawJSON),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update job: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := db.saveSkills(tx, id, job.Requirements.TechnicalSkills); err != nil {
+		return fmt.Errorf("save skills: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
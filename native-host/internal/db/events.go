@@ -0,0 +1,245 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"native-host/internal/models"
+)
+
+// JobEventKinds are the accepted values for JobEvent.Kind. Interview slots
+// are not events; they come from the interviews table.
+var JobEventKinds = []string{"follow_up", "deadline", "closing_date", "other"}
+
+// JobEvent is a dated item attached to a job, such as a follow-up
+// reminder or the date applications close.
+type JobEvent struct {
+	ID        int64
+	JobID     int64
+	Kind      string
+	Title     string
+	StartsAt  time.Time
+	EndsAt    time.Time // zero when open-ended
+	AllDay    bool
+	Notes     string
+	UpdatedAt time.Time
+}
+
+func (ev *JobEvent) Validate() error {
+	if !models.Contains(JobEventKinds, ev.Kind) {
+		return fmt.Errorf("event kind %q is not one of %s", ev.Kind, strings.Join(JobEventKinds, ", "))
+	}
+	if ev.StartsAt.IsZero() {
+		return fmt.Errorf("event start is required")
+	}
+	if !ev.EndsAt.IsZero() && ev.EndsAt.Before(ev.StartsAt) {
+		return fmt.Errorf("event ends before it starts")
+	}
+	return nil
+}
+
+func endsAtValue(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC()
+}
+
+const jobEventColumns = `id, job_id, kind, title, starts_at, ends_at, all_day, notes, updated_at`
+
+func scanJobEvent(row interface{ Scan(...any) error }) (JobEvent, error) {
+	var ev JobEvent
+	var title, notes sql.NullString
+	var endsAt, updatedAt sql.NullTime
+	var allDay sql.NullBool
+
+	if err := row.Scan(&ev.ID, &ev.JobID, &ev.Kind, &title, &ev.StartsAt, &endsAt, &allDay, &notes, &updatedAt); err != nil {
+		return ev, err
+	}
+	ev.Title = title.String
+	ev.EndsAt = endsAt.Time
+	ev.AllDay = allDay.Bool
+	ev.Notes = notes.String
+	ev.UpdatedAt = updatedAt.Time
+	return ev, nil
+}
+
+// GetJobEvents returns a job's events in chronological order.
+func (db *DB) GetJobEvents(jobID int64) ([]JobEvent, error) {
+	rows, err := db.Query(`SELECT `+jobEventColumns+` FROM job_events WHERE job_id = ? ORDER BY starts_at, id`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []JobEvent{}
+	for rows.Next() {
+		ev, err := scanJobEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+func (db *DB) GetJobEvent(id int64) (*JobEvent, error) {
+	ev, err := scanJobEvent(db.QueryRow(`SELECT `+jobEventColumns+` FROM job_events WHERE id = ?`, id))
+	if err != nil {
+		return nil, err
+	}
+	return &ev, nil
+}
+
+func (db *DB) CreateJobEvent(ev *JobEvent) (int64, error) {
+	if err := ev.Validate(); err != nil {
+		return 0, err
+	}
+
+	query := `
+        INSERT INTO job_events (job_id, kind, title, starts_at, ends_at, all_day, notes)
+        SELECT id, ?, ?, ?, ?, ?, ? FROM jobs WHERE id = ?
+    `
+	result, err := db.Exec(query, ev.Kind, ev.Title, ev.StartsAt.UTC(), endsAtValue(ev.EndsAt), ev.AllDay, ev.Notes, ev.JobID)
+	if err != nil {
+		return 0, fmt.Errorf("insert event: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return 0, fmt.Errorf("job %d not found", ev.JobID)
+	}
+	return result.LastInsertId()
+}
+
+func (db *DB) UpdateJobEvent(ev *JobEvent) error {
+	if err := ev.Validate(); err != nil {
+		return err
+	}
+
+	query := `
+        UPDATE job_events SET
+            kind = ?, title = ?, starts_at = ?, ends_at = ?, all_day = ?, notes = ?,
+            revision = IFNULL(revision, 0) + 1, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	result, err := db.Exec(query, ev.Kind, ev.Title, ev.StartsAt.UTC(), endsAtValue(ev.EndsAt), ev.AllDay, ev.Notes, ev.ID)
+	if err != nil {
+		return fmt.Errorf("update event: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (db *DB) DeleteJobEvent(id int64) error {
+	_, err := db.Exec(`DELETE FROM job_events WHERE id = ?`, id)
+	return err
+}
+
+// CalendarEntry is a job event or a scheduled interview, together with the
+// job it belongs to, ready to be rendered into a calendar.
+type CalendarEntry struct {
+	Source      string // "event" or "interview"
+	ID          int64
+	JobID       int64
+	JobTitle    string
+	CompanyName string
+	SourceURL   string
+	Kind        string // event kind or interview round type
+	Title       string
+	StartsAt    time.Time
+	EndsAt      time.Time
+	AllDay      bool
+	Notes       string
+	Attendees   []string // interviewers
+	Cancelled   bool
+	Revision    int64 // number of updates since the entry was created
+	UpdatedAt   time.Time
+}
+
+// ListCalendarEntries returns the job events and scheduled interviews of one
+// job, or of every job when jobID is 0, in chronological order.
+func (db *DB) ListCalendarEntries(jobID int64) ([]CalendarEntry, error) {
+	eventsQuery := `
+        SELECT e.id, j.id, IFNULL(j.job_title, ''), IFNULL(j.company_name, ''), j.source_url,
+               e.kind, e.title, e.starts_at, e.ends_at, e.all_day, e.notes,
+               IFNULL(e.revision, 0), e.updated_at
+        FROM job_events e
+        JOIN jobs j ON j.id = e.job_id
+        WHERE (? = 0 OR j.id = ?)
+    `
+	rows, err := db.Query(eventsQuery, jobID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []CalendarEntry
+	for rows.Next() {
+		e := CalendarEntry{Source: "event"}
+		var title, notes sql.NullString
+		var endsAt, updatedAt sql.NullTime
+		var allDay sql.NullBool
+		if err := rows.Scan(&e.ID, &e.JobID, &e.JobTitle, &e.CompanyName, &e.SourceURL,
+			&e.Kind, &title, &e.StartsAt, &endsAt, &allDay, &notes, &e.Revision, &updatedAt); err != nil {
+			return nil, err
+		}
+		e.Title = title.String
+		e.EndsAt = endsAt.Time
+		e.AllDay = allDay.Bool
+		e.Notes = notes.String
+		e.UpdatedAt = updatedAt.Time
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	interviewsQuery := `
+        SELECT i.id, j.id, IFNULL(j.job_title, ''), IFNULL(j.company_name, ''), j.source_url,
+               i.round_type, i.interviewers, i.scheduled_at, i.duration_minutes, i.outcome,
+               i.feedback, IFNULL(i.revision, 0), i.updated_at
+        FROM interviews i
+        JOIN jobs j ON j.id = i.job_id
+        WHERE i.scheduled_at IS NOT NULL AND (? = 0 OR j.id = ?)
+    `
+	ivRows, err := db.Query(interviewsQuery, jobID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer ivRows.Close()
+
+	for ivRows.Next() {
+		e := CalendarEntry{Source: "interview"}
+		var interviewers, outcome, feedback sql.NullString
+		var duration sql.NullInt64
+		var updatedAt sql.NullTime
+		if err := ivRows.Scan(&e.ID, &e.JobID, &e.JobTitle, &e.CompanyName, &e.SourceURL,
+			&e.Kind, &interviewers, &e.StartsAt, &duration, &outcome, &feedback, &e.Revision, &updatedAt); err != nil {
+			return nil, err
+		}
+		minutes := duration.Int64
+		if minutes <= 0 {
+			minutes = 60
+		}
+		e.EndsAt = e.StartsAt.Add(time.Duration(minutes) * time.Minute)
+		if e.Attendees, err = parseInterviewers(interviewers.String); err != nil {
+			return nil, err
+		}
+		e.Notes = feedback.String
+		e.Cancelled = outcome.String == "cancelled"
+		e.UpdatedAt = updatedAt.Time
+		entries = append(entries, e)
+	}
+	if err := ivRows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].StartsAt.Before(entries[b].StartsAt)
+	})
+	return entries, nil
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"native-host/internal/grounding"
+)
+
+// SaveEvidence replaces the evidence quotes stored for a job.
+func (db *DB) SaveEvidence(jobID int64, spans []grounding.Span) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM job_evidence WHERE job_id = ?`, jobID); err != nil {
+		return fmt.Errorf("clear evidence: %w", err)
+	}
+	for _, s := range spans {
+		var start, end any
+		if s.Found() {
+			start, end = s.Start, s.End
+		}
+		if _, err := tx.Exec(`
+            INSERT INTO job_evidence (job_id, field, quote, start_offset, end_offset)
+            VALUES (?, ?, ?, ?, ?)
+        `, jobID, s.Field, s.Quote, start, end); err != nil {
+			return fmt.Errorf("insert evidence: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetEvidence returns a job's evidence quotes in the order of the fields
+// of models.Evidence.
+func (db *DB) GetEvidence(jobID int64) ([]grounding.Span, error) {
+	rows, err := db.Query(`
+        SELECT field, quote, start_offset, end_offset
+        FROM job_evidence
+        WHERE job_id = ?
+        ORDER BY CASE field
+            WHEN 'salary' THEN 1
+            WHEN 'seniority_level' THEN 2
+            WHEN 'workplace_type' THEN 3
+            WHEN 'years_experience' THEN 4
+            WHEN 'visa_sponsorship' THEN 5
+            ELSE 6
+        END, field
+    `, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spans := []grounding.Span{}
+	for rows.Next() {
+		var s grounding.Span
+		var start, end sql.NullInt64
+		if err := rows.Scan(&s.Field, &s.Quote, &start, &end); err != nil {
+			return nil, err
+		}
+		s.Start, s.End = -1, -1
+		if start.Valid && end.Valid {
+			s.Start, s.End = int(start.Int64), int(end.Int64)
+		}
+		spans = append(spans, s)
+	}
+	return spans, rows.Err()
+}
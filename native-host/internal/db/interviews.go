@@ -0,0 +1,312 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"native-host/internal/models"
+)
+
+// Accepted values for Interview.RoundType and Interview.Outcome.
+var (
+	InterviewRoundTypes = []string{
+		"phone_screen", "technical", "take_home", "pair_programming",
+		"system_design", "behavioral", "onsite", "final", "other",
+	}
+	InterviewOutcomes = []string{"pending", "passed", "failed", "cancelled"}
+)
+
+// Interview is one actual interview round for a job.
+type Interview struct {
+	ID              int64
+	JobID           int64
+	RoundType       string
+	ScheduledAt     string // RFC 3339, empty when not scheduled yet
+	DurationMinutes int
+	Interviewers    []string
+	Outcome         string
+	Feedback        string
+	UpdatedAt       string
+}
+
+func (iv *Interview) Validate() error {
+	if !models.Contains(InterviewRoundTypes, iv.RoundType) {
+		return fmt.Errorf("round type %q is not one of %s", iv.RoundType, strings.Join(InterviewRoundTypes, ", "))
+	}
+	if iv.Outcome == "" {
+		iv.Outcome = "pending"
+	}
+	if !models.Contains(InterviewOutcomes, iv.Outcome) {
+		return fmt.Errorf("outcome %q is not one of %s", iv.Outcome, strings.Join(InterviewOutcomes, ", "))
+	}
+	if iv.ScheduledAt != "" {
+		if _, err := time.Parse(time.RFC3339, iv.ScheduledAt); err != nil {
+			return fmt.Errorf("invalid scheduled time: %w", err)
+		}
+	}
+	if iv.DurationMinutes < 0 {
+		return fmt.Errorf("duration cannot be negative")
+	}
+	return nil
+}
+
+func scheduledAtValue(s string) (any, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return t.UTC(), nil
+}
+
+// interviewersValue stores names as a JSON array, since names can contain
+// commas ("Doe, Jane"). No names are stored as NULL.
+func interviewersValue(names []string) (any, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// parseInterviewers reads interviewersValue.
+func parseInterviewers(s string) ([]string, error) {
+	names := []string{}
+	if s == "" {
+		return names, nil
+	}
+	if err := json.Unmarshal([]byte(s), &names); err != nil {
+		return nil, fmt.Errorf("parse interviewers: %w", err)
+	}
+	return names, nil
+}
+
+const interviewColumns = `id, job_id, round_type, scheduled_at, duration_minutes, interviewers, outcome, feedback, updated_at`
+
+func scanInterview(row interface{ Scan(...any) error }) (Interview, error) {
+	var iv Interview
+	var scheduledAt sql.NullTime
+	var duration sql.NullInt64
+	var interviewers, outcome, feedback, updatedAt sql.NullString
+
+	if err := row.Scan(&iv.ID, &iv.JobID, &iv.RoundType, &scheduledAt, &duration,
+		&interviewers, &outcome, &feedback, &updatedAt); err != nil {
+		return iv, err
+	}
+
+	if scheduledAt.Valid {
+		iv.ScheduledAt = scheduledAt.Time.UTC().Format(time.RFC3339)
+	}
+	iv.DurationMinutes = int(duration.Int64)
+	names, err := parseInterviewers(interviewers.String)
+	if err != nil {
+		return iv, err
+	}
+	iv.Interviewers = names
+	iv.Outcome = outcome.String
+	iv.Feedback = feedback.String
+	iv.UpdatedAt = updatedAt.String
+	return iv, nil
+}
+
+// GetJobInterviews returns a job's interviews, earliest first; unscheduled
+// rounds sort last.
+func (db *DB) GetJobInterviews(jobID int64) ([]Interview, error) {
+	query := `
+        SELECT ` + interviewColumns + `
+        FROM interviews
+        WHERE job_id = ?
+        ORDER BY scheduled_at IS NULL, scheduled_at, id
+    `
+	rows, err := db.Query(query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	interviews := []Interview{}
+	for rows.Next() {
+		iv, err := scanInterview(rows)
+		if err != nil {
+			return nil, err
+		}
+		interviews = append(interviews, iv)
+	}
+	return interviews, rows.Err()
+}
+
+func (db *DB) GetInterview(id int64) (*Interview, error) {
+	iv, err := scanInterview(db.QueryRow(`SELECT `+interviewColumns+` FROM interviews WHERE id = ?`, id))
+	if err != nil {
+		return nil, err
+	}
+	return &iv, nil
+}
+
+func (db *DB) CreateInterview(iv *Interview) (int64, error) {
+	if err := iv.Validate(); err != nil {
+		return 0, err
+	}
+	scheduledAt, err := scheduledAtValue(iv.ScheduledAt)
+	if err != nil {
+		return 0, err
+	}
+	interviewers, err := interviewersValue(iv.Interviewers)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+        INSERT INTO interviews (job_id, round_type, scheduled_at, duration_minutes, interviewers, outcome, feedback)
+        SELECT id, ?, ?, ?, ?, ?, ? FROM jobs WHERE id = ?
+    `
+	result, err := db.Exec(query, iv.RoundType, scheduledAt, iv.DurationMinutes,
+		interviewers, iv.Outcome, iv.Feedback, iv.JobID)
+	if err != nil {
+		return 0, fmt.Errorf("insert interview: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return 0, fmt.Errorf("job %d not found", iv.JobID)
+	}
+	return result.LastInsertId()
+}
+
+func (db *DB) UpdateInterview(iv *Interview) error {
+	if err := iv.Validate(); err != nil {
+		return err
+	}
+	scheduledAt, err := scheduledAtValue(iv.ScheduledAt)
+	if err != nil {
+		return err
+	}
+	interviewers, err := interviewersValue(iv.Interviewers)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        UPDATE interviews SET
+            round_type = ?, scheduled_at = ?, duration_minutes = ?, interviewers = ?,
+            outcome = ?, feedback = ?, revision = IFNULL(revision, 0) + 1,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	result, err := db.Exec(query, iv.RoundType, scheduledAt, iv.DurationMinutes,
+		interviewers, iv.Outcome, iv.Feedback, iv.ID)
+	if err != nil {
+		return fmt.Errorf("update interview: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (db *DB) DeleteInterview(id int64) error {
+	_, err := db.Exec(`DELETE FROM interviews WHERE id = ?`, id)
+	return err
+}
+
+// InterviewComparison contrasts what a posting announced about its
+// interview process with the rounds that actually happened.
+type InterviewComparison struct {
+	JobID             int64
+	JobTitle          string
+	CompanyName       string
+	Status            string
+	PromisedRounds    int
+	ActualRounds      int
+	PromisedTakeHome  bool
+	ActualTakeHome    bool
+	ProcessIsFinished bool
+}
+
+// InterviewRoundStats aggregates InterviewComparison rows. Fewer rounds
+// than promised is only counted once the process has finished (offer or
+// rejected), since an ongoing process naturally has fewer rounds so far.
+type InterviewRoundStats struct {
+	JobsWithInterviews  int
+	JobsWithPromise     int
+	AvgPromisedRounds   float64
+	AvgActualRounds     float64
+	MoreThanPromised    int
+	AsPromised          int
+	FewerThanPromised   int
+	UnannouncedTakeHome int
+	Jobs                []InterviewComparison
+}
+
+// GetInterviewRoundStats compares recorded interviews (cancelled rounds
+// excluded) against the extracted interview_rounds and has_take_home for
+// every job matching f that has at least one interview.
+func (db *DB) GetInterviewRoundStats(f AnalyticsFilter) (*InterviewRoundStats, error) {
+	query := `
+        SELECT
+            j.id,
+            IFNULL(j.job_title, ''),
+            IFNULL(j.company_name, ''),
+            IFNULL(j.status, ''),
+            IFNULL(j.interview_rounds, 0),
+            IFNULL(j.has_take_home, 0),
+            COUNT(i.id) AS actual_rounds,
+            MAX(i.round_type = 'take_home') AS actual_take_home
+        FROM jobs j
+        JOIN interviews i ON i.job_id = j.id AND i.outcome != 'cancelled'
+        WHERE (? = '' OR j.posting_language = ?)
+          AND (? OR IFNULL(j.needs_review, 0) = 0)
+        GROUP BY j.id
+        ORDER BY j.id
+    `
+	rows, err := db.Query(query, f.Language, f.Language, f.IncludeUnreviewed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &InterviewRoundStats{Jobs: []InterviewComparison{}}
+	var promisedSum, actualSum int
+	for rows.Next() {
+		var c InterviewComparison
+		if err := rows.Scan(&c.JobID, &c.JobTitle, &c.CompanyName, &c.Status,
+			&c.PromisedRounds, &c.PromisedTakeHome, &c.ActualRounds, &c.ActualTakeHome); err != nil {
+			return nil, err
+		}
+		c.ProcessIsFinished = c.Status == "offer" || c.Status == "rejected"
+
+		stats.JobsWithInterviews++
+		if c.ActualTakeHome && !c.PromisedTakeHome {
+			stats.UnannouncedTakeHome++
+		}
+		if c.PromisedRounds > 0 {
+			stats.JobsWithPromise++
+			promisedSum += c.PromisedRounds
+			actualSum += c.ActualRounds
+			switch {
+			case c.ActualRounds > c.PromisedRounds:
+				stats.MoreThanPromised++
+			case c.ActualRounds == c.PromisedRounds:
+				stats.AsPromised++
+			case c.ProcessIsFinished:
+				stats.FewerThanPromised++
+			}
+		}
+		stats.Jobs = append(stats.Jobs, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if stats.JobsWithPromise > 0 {
+		stats.AvgPromisedRounds = float64(promisedSum) / float64(stats.JobsWithPromise)
+		stats.AvgActualRounds = float64(actualSum) / float64(stats.JobsWithPromise)
+	}
+	return stats, nil
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LivenessTarget is a job whose posting should be re-fetched.
+type LivenessTarget struct {
+	ID        int64
+	SourceURL string
+}
+
+// ListLivenessTargets returns the job with the given id, or every job when
+// id is 0. Jobs already marked closed are skipped unless includeClosed.
+func (db *DB) ListLivenessTargets(id int64, includeClosed bool) ([]LivenessTarget, error) {
+	query := `
+        SELECT id, source_url
+        FROM jobs
+        WHERE (? = 0 OR id = ?)
+          AND (? OR closed_at IS NULL)
+          AND source_url LIKE 'http%'
+        ORDER BY last_checked_at IS NOT NULL, last_checked_at, id
+    `
+	rows, err := db.Query(query, id, id, includeClosed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []LivenessTarget
+	for rows.Next() {
+		var t LivenessTarget
+		if err := rows.Scan(&t.ID, &t.SourceURL); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+// RecordLivenessCheck stores when a job was checked and, if the posting was
+// found closed, when and why. The first closed_at is kept on re-checks so
+// lifetimes stay accurate; a posting found open again is reopened, since
+// one failed fetch should not close a job for good. updated_at is left
+// alone: a background check is not a change to the job.
+func (db *DB) RecordLivenessCheck(id int64, checkedAt time.Time, closed bool, reason string) error {
+	if !closed {
+		query := `
+            UPDATE jobs SET last_checked_at = ?, closed_at = NULL, closed_reason = NULL
+            WHERE id = ?
+        `
+		_, err := db.Exec(query, checkedAt.UTC(), id)
+		return err
+	}
+
+	query := `
+        UPDATE jobs SET
+            last_checked_at = ?,
+            closed_at = COALESCE(closed_at, ?),
+            closed_reason = COALESCE(closed_reason, ?)
+        WHERE id = ?
+    `
+	_, err := db.Exec(query, checkedAt.UTC(), checkedAt.UTC(), reason, id)
+	return err
+}
+
+// PostingLifetimeStats summarizes how long postings stay up, measured from
+// when we first saved them to when a liveness check found them closed.
+type PostingLifetimeStats struct {
+	Open            int
+	Closed          int
+	NeverChecked    int
+	AvgLifetimeDays float64
+	MinLifetimeDays float64
+	MaxLifetimeDays float64
+}
+
+func (db *DB) GetPostingLifetimeStats(f AnalyticsFilter) (*PostingLifetimeStats, error) {
+	query := `
+        SELECT
+            SUM(CASE WHEN closed_at IS NULL THEN 1 ELSE 0 END),
+            SUM(CASE WHEN closed_at IS NOT NULL THEN 1 ELSE 0 END),
+            SUM(CASE WHEN last_checked_at IS NULL THEN 1 ELSE 0 END),
+            AVG(julianday(closed_at) - julianday(created_at)),
+            MIN(julianday(closed_at) - julianday(created_at)),
+            MAX(julianday(closed_at) - julianday(created_at))
+        FROM jobs
+        WHERE (? = '' OR posting_language = ?)
+          AND (? OR IFNULL(needs_review, 0) = 0)
+    `
+	var open, closed, neverChecked sql.NullInt64
+	var avg, min, max sql.NullFloat64
+	if err := db.QueryRow(query, f.Language, f.Language, f.IncludeUnreviewed).Scan(&open, &closed, &neverChecked, &avg, &min, &max); err != nil {
+		return nil, err
+	}
+
+	return &PostingLifetimeStats{
+		Open:            int(open.Int64),
+		Closed:          int(closed.Int64),
+		NeverChecked:    int(neverChecked.Int64),
+		AvgLifetimeDays: avg.Float64,
+		MinLifetimeDays: min.Float64,
+		MaxLifetimeDays: max.Float64,
+	}, nil
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"native-host/internal/models"
+	"strings"
+	"time"
 )
 
 type JobSummary struct {
@@ -19,10 +21,16 @@ type JobSummary struct {
 	Status        string
 	ExtractedAt   string
 	SourceURL     string
+	Tags          []string
+	ClosedAt      string
+	Language      string
+	Suspicious    bool // injection patterns were found in the posting
+	NeedsReview   bool
 }
 
 // ListJobs uses existing columns: location_full, job_type, workplace_type, etc.
-func (db *DB) ListJobs(limit, offset int, status string) ([]JobSummary, error) {
+// An empty status, tag or language disables that filter.
+func (db *DB) ListJobs(limit, offset int, status, tag, language string) ([]JobSummary, error) {
 	query := `
         SELECT 
             id, 
@@ -36,14 +44,25 @@ func (db *DB) ListJobs(limit, offset int, status string) ([]JobSummary, error) {
             salary_min || '-' || salary_max || ' ' || IFNULL(salary_currency, '') as salary_range,
             status, 
             extracted_at, 
-            source_url
+            source_url,
+            (SELECT GROUP_CONCAT(t.name, ',')
+               FROM job_tags jt JOIN tags t ON t.id = jt.tag_id
+              WHERE jt.job_id = jobs.id) AS tags,
+            closed_at,
+            IFNULL(posting_language, ''),
+            injection_flags IS NOT NULL,
+            IFNULL(needs_review, 0)
         FROM jobs
         WHERE (? = '' OR status = ?)
+          AND (? = '' OR posting_language = ?)
+          AND (? = '' OR id IN (
+                SELECT jt.job_id FROM job_tags jt JOIN tags t ON t.id = jt.tag_id
+                 WHERE t.name = ? COLLATE NOCASE))
         ORDER BY extracted_at DESC
         LIMIT ? OFFSET ?
     `
 
-	rows, err := db.Query(query, status, status, limit, offset)
+	rows, err := db.Query(query, status, status, language, language, tag, tag, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +72,8 @@ func (db *DB) ListJobs(limit, offset int, status string) ([]JobSummary, error) {
 	for rows.Next() {
 		var job JobSummary
 		var salaryRange sql.NullString
+		var tags sql.NullString
+		var closedAt sql.NullTime
 
 		if err := rows.Scan(
 			&job.ID,
@@ -67,40 +88,115 @@ func (db *DB) ListJobs(limit, offset int, status string) ([]JobSummary, error) {
 			&job.Status,
 			&job.ExtractedAt,
 			&job.SourceURL,
+			&tags,
+			&closedAt,
+			&job.Language,
+			&job.Suspicious,
+			&job.NeedsReview,
 		); err != nil {
 			return nil, err
 		}
 
 		job.SalaryRange = salaryRange.String
+		if tags.Valid && tags.String != "" {
+			job.Tags = strings.Split(tags.String, ",")
+		}
+		if closedAt.Valid {
+			job.ClosedAt = closedAt.Time.UTC().Format(time.RFC3339)
+		}
 		jobs = append(jobs, job)
 	}
 
 	return jobs, nil
 }
 
-func (db *DB) GetJobByID(id int64) (*models.JobPosting, string, string, int, error) {
-	query := `SELECT raw_json, status, notes, rating FROM jobs WHERE id = ?`
+// JobRecord holds the tracking columns of a job that live outside raw_json.
+type JobRecord struct {
+	Status      string
+	Notes       string
+	Rating      int
+	AppliedDate string // YYYY-MM-DD, empty when not set
+
+	// Set by liveness checks; empty while the posting is still up.
+	ClosedAt     string
+	ClosedReason string
+
+	// Review of the extraction; ReviewedAt is empty until approved.
+	NeedsReview   bool
+	ReviewReasons []string
+	ReviewedAt    string
+}
+
+func (db *DB) GetJobByID(id int64) (*models.JobPosting, *JobRecord, error) {
+	query := `
+        SELECT raw_json, status, notes, rating, applied_date, closed_at, closed_reason,
+               IFNULL(needs_review, 0), review_reasons, reviewed_at
+        FROM jobs WHERE id = ?
+    `
 
 	var rawJSON string
 	var status sql.NullString
 	var notes sql.NullString
 	var rating sql.NullInt64
-
-	if err := db.QueryRow(query, id).Scan(&rawJSON, &status, &notes, &rating); err != nil {
-		return nil, "", "", 0, err
+	var appliedDate sql.NullTime
+	var closedAt sql.NullTime
+	var closedReason sql.NullString
+	var needsReview bool
+	var reviewReasons sql.NullString
+	var reviewedAt sql.NullTime
+
+	if err := db.QueryRow(query, id).Scan(&rawJSON, &status, &notes, &rating, &appliedDate, &closedAt, &closedReason,
+		&needsReview, &reviewReasons, &reviewedAt); err != nil {
+		return nil, nil, err
 	}
 
 	var job models.JobPosting
 	if err := json.Unmarshal([]byte(rawJSON), &job); err != nil {
-		return nil, "", "", 0, err
+		return nil, nil, err
+	}
+
+	rec := &JobRecord{
+		Status:       status.String,
+		Notes:        notes.String,
+		Rating:       int(rating.Int64),
+		ClosedReason: closedReason.String,
+		NeedsReview:  needsReview,
+	}
+	if appliedDate.Valid {
+		rec.AppliedDate = appliedDate.Time.Format("2006-01-02")
+	}
+	if closedAt.Valid {
+		rec.ClosedAt = closedAt.Time.UTC().Format(time.RFC3339)
+	}
+	if reviewReasons.Valid && reviewReasons.String != "" {
+		rec.ReviewReasons = strings.Split(reviewReasons.String, ",")
+	}
+	if reviewedAt.Valid {
+		rec.ReviewedAt = reviewedAt.Time.UTC().Format(time.RFC3339)
 	}
 
-	return &job, status.String, notes.String, int(rating.Int64), nil
+	return &job, rec, nil
+}
+
+// FindJobByURL returns the id of the job saved from sourceURL, or 0 when
+// there is none.
+func (db *DB) FindJobByURL(sourceURL string) (int64, error) {
+	var id int64
+	err := db.QueryRow(`SELECT id FROM jobs WHERE source_url = ?`, sourceURL).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
 }
 
 func (db *DB) UpdateJobStatus(id int64, status string) error {
-	query := `UPDATE jobs SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
-	_, err := db.Exec(query, status, id)
+	query := `
+        UPDATE jobs SET
+            status_changed_at = CASE WHEN status IS ? THEN status_changed_at ELSE CURRENT_TIMESTAMP END,
+            status = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+	_, err := db.Exec(query, status, status, id)
 	return err
 }
 
@@ -116,6 +212,17 @@ func (db *DB) UpdateJobRating(id int64, rating int) error {
 	return err
 }
 
+// UpdateJobAppliedDate sets applied_date, or clears it when date is nil.
+func (db *DB) UpdateJobAppliedDate(id int64, date *time.Time) error {
+	query := `UPDATE jobs SET applied_date = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	var value any
+	if date != nil {
+		value = *date
+	}
+	_, err := db.Exec(query, value, id)
+	return err
+}
+
 func (db *DB) SearchJobs(search string) ([]JobSummary, error) {
 	query := `
 		SELECT id, job_title, company_name, location_full, workplace_type, status, extracted_at, source_url
@@ -153,20 +260,23 @@ func (db *DB) SearchJobs(search string) ([]JobSummary, error) {
 	return jobs, nil
 }
 
-func (db *DB) GetJobStats() (map[string]int, error) {
+// GetJobStats counts the jobs matching f per pipeline stage.
+func (db *DB) GetJobStats(f AnalyticsFilter) (map[string]int, error) {
 	query := `
 		SELECT 
 			COUNT(*) as total,
-			SUM(CASE WHEN status = 'saved' THEN 1 ELSE 0 END) as saved,
-			SUM(CASE WHEN status = 'applied' THEN 1 ELSE 0 END) as applied,
-			SUM(CASE WHEN status = 'interview' THEN 1 ELSE 0 END) as interview,
-			SUM(CASE WHEN status = 'offer' THEN 1 ELSE 0 END) as offer,
-			SUM(CASE WHEN status = 'rejected' THEN 1 ELSE 0 END) as rejected
+			IFNULL(SUM(CASE WHEN status = 'saved' THEN 1 ELSE 0 END), 0) as saved,
+			IFNULL(SUM(CASE WHEN status = 'applied' THEN 1 ELSE 0 END), 0) as applied,
+			IFNULL(SUM(CASE WHEN status = 'interview' THEN 1 ELSE 0 END), 0) as interview,
+			IFNULL(SUM(CASE WHEN status = 'offer' THEN 1 ELSE 0 END), 0) as offer,
+			IFNULL(SUM(CASE WHEN status = 'rejected' THEN 1 ELSE 0 END), 0) as rejected
 		FROM jobs
+		WHERE (? = '' OR posting_language = ?)
+		  AND (? OR IFNULL(needs_review, 0) = 0)
 	`
 
 	var total, saved, applied, interview, offer, rejected int
-	if err := db.QueryRow(query).Scan(&total, &saved, &applied, &interview, &offer, &rejected); err != nil {
+	if err := db.QueryRow(query, f.Language, f.Language, f.IncludeUnreviewed).Scan(&total, &saved, &applied, &interview, &offer, &rejected); err != nil {
 		return nil, err
 	}
 
@@ -181,15 +291,62 @@ func (db *DB) GetJobStats() (map[string]int, error) {
 }
 
 func (db *DB) DeleteJob(id int64) error {
-	// Also delete from job_skills to keep it clean
-	_, err := db.Exec(`DELETE FROM job_skills WHERE job_id = ?`, id)
-	if err != nil {
+	// Also delete from the per-job tables to keep them clean
+	for _, table := range []string{"job_skills", "job_tags", "job_contacts", "interviews", "job_events", "reminder_state", "job_field_checks", "job_evidence"} {
+		if _, err := db.Exec(`DELETE FROM `+table+` WHERE job_id = ?`, id); err != nil {
+			return err
+		}
+	}
+	// Extraction runs stay for spend accounting.
+	if _, err := db.Exec(`UPDATE extraction_runs SET job_id = NULL WHERE job_id = ?`, id); err != nil {
 		return err
 	}
-	_, err = db.Exec(`DELETE FROM jobs WHERE id = ?`, id)
+	_, err := db.Exec(`DELETE FROM jobs WHERE id = ?`, id)
 	return err
 }
 
+// AnalyticsFilter narrows the jobs analytics are computed over. Zero
+// fields match every job, except that jobs whose extraction awaits review
+// are left out unless IncludeUnreviewed.
+type AnalyticsFilter struct {
+	Language          string
+	IncludeUnreviewed bool
+}
+
+// LanguageCount is the number of jobs posted in one language; Language is
+// empty for jobs whose language is unknown.
+type LanguageCount struct {
+	Language string
+	Count    int
+}
+
+// GetLanguageStats counts jobs per posting language, most common first.
+// f.Language is ignored so that every language stays listed to choose
+// from.
+func (db *DB) GetLanguageStats(f AnalyticsFilter) ([]LanguageCount, error) {
+	rows, err := db.Query(`
+        SELECT IFNULL(posting_language, ''), COUNT(*) AS cnt
+        FROM jobs
+        WHERE ? OR IFNULL(needs_review, 0) = 0
+        GROUP BY IFNULL(posting_language, '')
+        ORDER BY cnt DESC, 1
+    `, f.IncludeUnreviewed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []LanguageCount
+	for rows.Next() {
+		var lc LanguageCount
+		if err := rows.Scan(&lc.Language, &lc.Count); err != nil {
+			return nil, err
+		}
+		res = append(res, lc)
+	}
+	return res, rows.Err()
+}
+
 // SkillSummary is used for analytics responses.
 type SkillSummary struct {
 	SkillName     string
@@ -225,7 +382,7 @@ func (db *DB) GetTopSkills(limit int) ([]SkillSummary, error) {
 	return res, nil
 }
 
-func (db *DB) GetSkillLocations(skill string, limit int) ([]struct {
+func (db *DB) GetSkillLocations(skill string, limit int, f AnalyticsFilter) ([]struct {
 	Location string
 	Count    int
 }, error) {
@@ -236,11 +393,13 @@ func (db *DB) GetSkillLocations(skill string, limit int) ([]struct {
         FROM job_skills s
         JOIN jobs j ON j.id = s.job_id
         WHERE s.skill_name = ?
+          AND (? = '' OR j.posting_language = ?)
+          AND (? OR IFNULL(j.needs_review, 0) = 0)
         GROUP BY COALESCE(j.location_city, j.location_full, 'Unknown')
         ORDER BY cnt DESC
         LIMIT ?
     `
-	rows, err := db.Query(query, skill, limit)
+	rows, err := db.Query(query, skill, f.Language, f.Language, f.IncludeUnreviewed, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -266,19 +425,22 @@ func (db *DB) GetSkillLocations(skill string, limit int) ([]struct {
 }
 
 // GetTopSkillsByCategory returns top skills per category.
-func (db *DB) GetTopSkillsByCategory(category string, limit int) ([]SkillSummary, error) {
+func (db *DB) GetTopSkillsByCategory(category string, limit int, f AnalyticsFilter) ([]SkillSummary, error) {
 	query := `
         SELECT 
-            skill_name,
-            skill_category,
+            s.skill_name,
+            s.skill_category,
             COUNT(*) AS cnt
-        FROM job_skills
-        WHERE skill_category = ?
-        GROUP BY skill_name, skill_category
-        ORDER BY cnt DESC, skill_name ASC
+        FROM job_skills s
+        JOIN jobs j ON j.id = s.job_id
+        WHERE s.skill_category = ?
+          AND (? = '' OR j.posting_language = ?)
+          AND (? OR IFNULL(j.needs_review, 0) = 0)
+        GROUP BY s.skill_name, s.skill_category
+        ORDER BY cnt DESC, s.skill_name ASC
         LIMIT ?
     `
-	rows, err := db.Query(query, category, limit)
+	rows, err := db.Query(query, category, f.Language, f.Language, f.IncludeUnreviewed, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -296,7 +458,7 @@ func (db *DB) GetTopSkillsByCategory(category string, limit int) ([]SkillSummary
 }
 
 // GetSkillsByStatus returns top skills per pipeline stage.
-func (db *DB) GetSkillsByStatus(limitPerStatus int) (map[string][]SkillSummary, error) {
+func (db *DB) GetSkillsByStatus(limitPerStatus int, f AnalyticsFilter) (map[string][]SkillSummary, error) {
 	query := `
         SELECT 
             j.status,
@@ -305,10 +467,12 @@ func (db *DB) GetSkillsByStatus(limitPerStatus int) (map[string][]SkillSummary,
             COUNT(*) AS cnt
         FROM job_skills s
         JOIN jobs j ON j.id = s.job_id
+        WHERE (? = '' OR j.posting_language = ?)
+          AND (? OR IFNULL(j.needs_review, 0) = 0)
         GROUP BY j.status, s.skill_name, s.skill_category
         ORDER BY j.status, cnt DESC
     `
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, f.Language, f.Language, f.IncludeUnreviewed)
 	if err != nil {
 		return nil, err
 	}
@@ -344,18 +508,20 @@ type JobTitleSummary struct {
 }
 
 // GetTopJobTitles returns most frequent job titles.
-func (db *DB) GetTopJobTitles(limit int) ([]JobTitleSummary, error) {
+func (db *DB) GetTopJobTitles(limit int, f AnalyticsFilter) ([]JobTitleSummary, error) {
 	query := `
         SELECT 
             job_title,
             COUNT(*) AS cnt
         FROM jobs
         WHERE job_title IS NOT NULL AND job_title != ''
+          AND (? = '' OR posting_language = ?)
+          AND (? OR IFNULL(needs_review, 0) = 0)
         GROUP BY job_title
         ORDER BY cnt DESC, job_title ASC
         LIMIT ?
     `
-	rows, err := db.Query(query, limit)
+	rows, err := db.Query(query, f.Language, f.Language, f.IncludeUnreviewed, limit)
 	if err != nil {
 		return nil, err
 	}
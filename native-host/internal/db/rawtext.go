@@ -0,0 +1,75 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"native-host/internal/models"
+)
+
+// RawText is the input a job was extracted from.
+type RawText struct {
+	JobID     int64
+	SourceURL string
+	Text      string // empty for jobs saved before raw text was stored
+	Path      string
+	Known     *models.JobPosting
+}
+
+// SaveRawText records the text a job was extracted from, so that it can be
+// re-extracted later. known may be nil.
+func (db *DB) SaveRawText(jobID int64, text, path string, known *models.JobPosting) error {
+	var knownJSON any
+	if known != nil {
+		data, err := json.Marshal(known)
+		if err != nil {
+			return fmt.Errorf("marshal known fields: %w", err)
+		}
+		knownJSON = string(data)
+	}
+
+	_, err := db.Exec(`UPDATE jobs SET raw_text = ?, raw_text_path = ?, known_json = ? WHERE id = ?`,
+		text, path, knownJSON, jobID)
+	return err
+}
+
+// ListRawTexts returns the raw text of one job (id > 0) or of every job.
+func (db *DB) ListRawTexts(id int64) ([]RawText, error) {
+	query := `
+        SELECT id, source_url, raw_text, raw_text_path, known_json
+        FROM jobs
+        WHERE (? = 0 OR id = ?)
+        ORDER BY id
+    `
+	rows, err := db.Query(query, id, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var texts []RawText
+	for rows.Next() {
+		var rt RawText
+		var text, path, knownJSON sql.NullString
+		if err := rows.Scan(&rt.JobID, &rt.SourceURL, &text, &path, &knownJSON); err != nil {
+			return nil, err
+		}
+		rt.Text = text.String
+		rt.Path = path.String
+		if knownJSON.Valid {
+			rt.Known = &models.JobPosting{}
+			if err := json.Unmarshal([]byte(knownJSON.String), rt.Known); err != nil {
+				return nil, fmt.Errorf("job %d: parse known fields: %w", rt.JobID, err)
+			}
+		}
+		texts = append(texts, rt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if id > 0 && len(texts) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return texts, nil
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ReminderCandidate holds the timestamps the reminder rules look at.
+// Zero times mean the value is not set.
+type ReminderCandidate struct {
+	JobID         int64
+	JobTitle      string
+	CompanyName   string
+	Status        string
+	StatusChanged time.Time // falls back to updated_at for jobs saved before it was tracked
+	AppliedDate   time.Time
+	LastInterview time.Time // latest past interview whose outcome is still pending
+}
+
+// ReminderState is the user's snooze/dismiss decision for one rule on one
+// job.
+type ReminderState struct {
+	SnoozedUntil time.Time
+	DismissedAt  time.Time
+}
+
+// ReminderKey identifies a reminder: one rule applied to one job.
+type ReminderKey struct {
+	JobID int64
+	Rule  string
+}
+
+// ListReminderCandidates returns every job that is still in the pipeline,
+// i.e. not rejected.
+func (db *DB) ListReminderCandidates(now time.Time) ([]ReminderCandidate, error) {
+	query := `
+        SELECT
+            j.id,
+            IFNULL(j.job_title, ''),
+            IFNULL(j.company_name, ''),
+            IFNULL(j.status, ''),
+            IFNULL(j.status_changed_at, j.updated_at),
+            j.applied_date,
+            (SELECT MAX(i.scheduled_at) FROM interviews i
+              WHERE i.job_id = j.id AND i.outcome = 'pending' AND i.scheduled_at <= ?)
+        FROM jobs j
+        WHERE IFNULL(j.status, '') != 'rejected'
+        ORDER BY j.id
+    `
+	rows, err := db.Query(query, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []ReminderCandidate
+	for rows.Next() {
+		var c ReminderCandidate
+		var appliedDate sql.NullTime
+		// MAX() and IFNULL() lose the column type, so the driver hands
+		// back text.
+		var statusChanged, lastInterview sql.NullString
+		if err := rows.Scan(&c.JobID, &c.JobTitle, &c.CompanyName, &c.Status,
+			&statusChanged, &appliedDate, &lastInterview); err != nil {
+			return nil, err
+		}
+		if statusChanged.Valid {
+			c.StatusChanged = parseSQLiteTime(statusChanged.String)
+		}
+		c.AppliedDate = appliedDate.Time
+		if lastInterview.Valid {
+			c.LastInterview = parseSQLiteTime(lastInterview.String)
+		}
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+// parseSQLiteTime parses the text forms the sqlite3 driver writes for
+// time.Time values and the ones CURRENT_TIMESTAMP produces.
+func parseSQLiteTime(s string) time.Time {
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02T15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05Z07:00",
+		time.RFC3339Nano,
+		"2006-01-02",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// GetReminderStates returns all stored snooze/dismiss decisions.
+func (db *DB) GetReminderStates() (map[ReminderKey]ReminderState, error) {
+	rows, err := db.Query(`SELECT job_id, rule, snoozed_until, dismissed_at FROM reminder_state`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make(map[ReminderKey]ReminderState)
+	for rows.Next() {
+		var k ReminderKey
+		var snoozed, dismissed sql.NullTime
+		if err := rows.Scan(&k.JobID, &k.Rule, &snoozed, &dismissed); err != nil {
+			return nil, err
+		}
+		states[k] = ReminderState{SnoozedUntil: snoozed.Time, DismissedAt: dismissed.Time}
+	}
+	return states, rows.Err()
+}
+
+// SnoozeReminder hides a reminder until the given time.
+func (db *DB) SnoozeReminder(jobID int64, rule string, until time.Time) error {
+	query := `
+        INSERT INTO reminder_state (job_id, rule, snoozed_until, updated_at)
+        VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+        ON CONFLICT(job_id, rule) DO UPDATE SET
+            snoozed_until = excluded.snoozed_until,
+            updated_at = CURRENT_TIMESTAMP
+    `
+	_, err := db.Exec(query, jobID, rule, until.UTC())
+	return err
+}
+
+// DismissReminder hides a reminder until the timestamp it is based on
+// moves past the dismissal, e.g. the job changes status and goes stale
+// again.
+func (db *DB) DismissReminder(jobID int64, rule string, at time.Time) error {
+	query := `
+        INSERT INTO reminder_state (job_id, rule, dismissed_at, updated_at)
+        VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+        ON CONFLICT(job_id, rule) DO UPDATE SET
+            dismissed_at = excluded.dismissed_at,
+            updated_at = CURRENT_TIMESTAMP
+    `
+	_, err := db.Exec(query, jobID, rule, at.UTC())
+	return err
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// SetReviewReasons records why a job's extraction needs review and flags it
+// when there are reasons. A new extraction (fresh) also clears an earlier
+// approval; otherwise an approved job stays approved.
+func (db *DB) SetReviewReasons(jobID int64, reasons []string, fresh bool) error {
+	query := `
+        UPDATE jobs
+        SET review_reasons = NULLIF(?, ''),
+            needs_review = (? AND reviewed_at IS NULL)
+        WHERE id = ?
+    `
+	if fresh {
+		query = `
+            UPDATE jobs
+            SET review_reasons = NULLIF(?, ''), needs_review = ?, reviewed_at = NULL
+            WHERE id = ?
+        `
+	}
+	_, err := db.Exec(query, strings.Join(reasons, ","), len(reasons) > 0, jobID)
+	return err
+}
+
+// ApproveExtraction takes a job out of the review queue. updated_at is left
+// alone: approving an extraction doesn't move the job along the pipeline.
+func (db *DB) ApproveExtraction(jobID int64) error {
+	result, err := db.Exec(`
+        UPDATE jobs
+        SET needs_review = 0, reviewed_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `, jobID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ReviewItem is a job waiting for its extraction to be reviewed.
+type ReviewItem struct {
+	JobID       int64
+	JobTitle    string
+	CompanyName string
+	SourceURL   string
+	ExtractedAt string
+	Reasons     []string
+}
+
+// ListReviewQueue returns the jobs that need review, oldest extraction
+// first.
+func (db *DB) ListReviewQueue() ([]ReviewItem, error) {
+	rows, err := db.Query(`
+        SELECT id, IFNULL(job_title, ''), IFNULL(company_name, ''), source_url, extracted_at, IFNULL(review_reasons, '')
+        FROM jobs
+        WHERE needs_review = 1
+        ORDER BY extracted_at, id
+    `)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []ReviewItem{}
+	for rows.Next() {
+		var it ReviewItem
+		var reasons string
+		if err := rows.Scan(&it.JobID, &it.JobTitle, &it.CompanyName, &it.SourceURL, &it.ExtractedAt, &reasons); err != nil {
+			return nil, err
+		}
+		if reasons != "" {
+			it.Reasons = strings.Split(reasons, ",")
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// CountUnreviewed is the number of jobs analytics leave out by default.
+func (db *DB) CountUnreviewed() (int, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM jobs WHERE needs_review = 1`).Scan(&n)
+	return n, err
+}
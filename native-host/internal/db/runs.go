@@ -0,0 +1,172 @@
+package db
+
+import "time"
+
+// Extraction run kinds.
+const (
+	RunExtract   = "extract"
+	RunReextract = "reextract"
+	RunCompare   = "compare"
+)
+
+// ExtractionRun is one call to a provider, successful or not.
+type ExtractionRun struct {
+	JobID            int64 // 0 when not tied to a saved job
+	Kind             string
+	Provider         string
+	Model            string
+	PromptVersion    string
+	PromptTokens     int
+	CompletionTokens int
+	Latency          time.Duration
+	Cost             float64
+	Priced           bool   // false when the model has no configured price
+	Error            string // empty on success
+}
+
+func (db *DB) SaveExtractionRun(r ExtractionRun) error {
+	var jobID, cost, runErr any
+	if r.JobID > 0 {
+		jobID = r.JobID
+	}
+	if r.Priced {
+		cost = r.Cost
+	}
+	if r.Error != "" {
+		runErr = r.Error
+	}
+
+	query := `
+        INSERT INTO extraction_runs (
+            job_id, kind, provider, model, prompt_version,
+            prompt_tokens, completion_tokens, latency_ms, cost_usd, error
+        ) VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?)
+    `
+	_, err := db.Exec(query, jobID, r.Kind, r.Provider, r.Model, r.PromptVersion,
+		r.PromptTokens, r.CompletionTokens, r.Latency.Milliseconds(), cost, runErr)
+	return err
+}
+
+// ProviderSpend is the estimated cost of a provider's runs since t.
+func (db *DB) ProviderSpend(provider string, since time.Time) (float64, error) {
+	var spent float64
+	err := db.QueryRow(`SELECT IFNULL(SUM(cost_usd), 0) FROM extraction_runs WHERE provider = ? AND created_at >= ?`,
+		provider, since.UTC()).Scan(&spent)
+	return spent, err
+}
+
+// ProviderRequests counts a provider's runs since t.
+func (db *DB) ProviderRequests(provider string, since time.Time) (int, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM extraction_runs WHERE provider = ? AND created_at >= ?`,
+		provider, since.UTC()).Scan(&n)
+	return n, err
+}
+
+// RunStats aggregates extraction runs.
+type RunStats struct {
+	Runs             int
+	Failures         int
+	PromptTokens     int
+	CompletionTokens int
+	Cost             float64 // sum over the priced runs
+	Unpriced         int
+	AvgLatencyMS     int
+}
+
+// FailureRate is between 0 and 1.
+func (s RunStats) FailureRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Runs)
+}
+
+// MonthStats are the runs of one calendar month (UTC), "2006-01".
+type MonthStats struct {
+	Month string
+	RunStats
+}
+
+// ModelStats are the runs of one provider/model.
+type ModelStats struct {
+	Provider string
+	Model    string
+	RunStats
+}
+
+// ExtractionStats covers the runs since a given time.
+type ExtractionStats struct {
+	Total  RunStats
+	Months []MonthStats // oldest first
+	Models []ModelStats // most expensive first
+}
+
+const runStatsColumns = `
+    COUNT(*),
+    COUNT(error),
+    IFNULL(SUM(prompt_tokens), 0),
+    IFNULL(SUM(completion_tokens), 0),
+    IFNULL(SUM(cost_usd), 0),
+    COUNT(*) - COUNT(cost_usd),
+    IFNULL(CAST(AVG(latency_ms) AS INTEGER), 0)
+`
+
+func scanRunStats(dest []any, s *RunStats) []any {
+	return append(dest, &s.Runs, &s.Failures, &s.PromptTokens, &s.CompletionTokens, &s.Cost, &s.Unpriced, &s.AvgLatencyMS)
+}
+
+// GetExtractionStats summarizes the runs since the given time, in total,
+// per month and per model.
+func (db *DB) GetExtractionStats(since time.Time) (*ExtractionStats, error) {
+	since = since.UTC()
+	stats := &ExtractionStats{}
+
+	err := db.QueryRow(`SELECT `+runStatsColumns+` FROM extraction_runs WHERE created_at >= ?`, since).
+		Scan(scanRunStats(nil, &stats.Total)...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+        SELECT strftime('%Y-%m', created_at) AS month, `+runStatsColumns+`
+        FROM extraction_runs
+        WHERE created_at >= ?
+        GROUP BY month
+        ORDER BY month
+    `, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m MonthStats
+		if err := rows.Scan(scanRunStats([]any{&m.Month}, &m.RunStats)...); err != nil {
+			return nil, err
+		}
+		stats.Months = append(stats.Months, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = db.Query(`
+        SELECT provider, model, `+runStatsColumns+`
+        FROM extraction_runs
+        WHERE created_at >= ?
+        GROUP BY provider, model
+        ORDER BY SUM(cost_usd) DESC, COUNT(*) DESC
+    `, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m ModelStats
+		if err := rows.Scan(scanRunStats([]any{&m.Provider, &m.Model}, &m.RunStats)...); err != nil {
+			return nil, err
+		}
+		stats.Models = append(stats.Models, m)
+	}
+	return stats, rows.Err()
+}
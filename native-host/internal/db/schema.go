@@ -1,5 +1,28 @@
 package db
 
+// columnMigrations lists columns added to existing tables after their
+// first release. Schema only creates missing tables, so each new column must
+// appear both in Schema and here.
+var columnMigrations = []struct {
+	table, column, definition string
+}{
+	{"jobs", "closed_at", "TIMESTAMP"},
+	{"jobs", "closed_reason", "TEXT"},
+	{"jobs", "last_checked_at", "TIMESTAMP"},
+	{"jobs", "raw_text", "TEXT"},
+	{"jobs", "raw_text_path", "TEXT"},
+	{"jobs", "known_json", "TEXT"},
+	{"jobs", "prompt_version", "TEXT"},
+	{"jobs", "posting_language", "TEXT"},
+	{"jobs", "injection_flags", "TEXT"},
+	{"jobs", "needs_review", "BOOLEAN DEFAULT 0"},
+	{"jobs", "review_reasons", "TEXT"},
+	{"jobs", "reviewed_at", "TIMESTAMP"},
+	{"jobs", "status_changed_at", "TIMESTAMP"},
+	{"interviews", "revision", "INTEGER DEFAULT 0"},
+	{"job_events", "revision", "INTEGER DEFAULT 0"},
+}
+
 const Schema = `
 CREATE TABLE IF NOT EXISTS jobs (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -60,12 +83,29 @@ CREATE TABLE IF NOT EXISTS jobs (
     
     -- Tracking
     status TEXT DEFAULT 'saved',
+    status_changed_at TIMESTAMP, -- NULL for jobs saved before it was tracked
     applied_date TIMESTAMP,
     notes TEXT,
     rating INTEGER,
     
+    -- Liveness
+    closed_at TIMESTAMP,
+    closed_reason TEXT,
+    last_checked_at TIMESTAMP,
+    
     -- Raw
     raw_json TEXT NOT NULL,
+    raw_text TEXT,              -- text the job was extracted from
+    raw_text_path TEXT,         -- job_<ts>_raw.txt in the output dir
+    known_json TEXT,            -- fields parsed from the page without the model
+    prompt_version TEXT,        -- extraction prompt, NULL for jobs saved before versioning
+    posting_language TEXT,      -- ISO 639-1 code detected from the text, NULL when unknown
+    injection_flags TEXT,       -- comma-separated injection patterns found in the text, NULL when none
+
+    -- Review of the extraction, independent of status
+    needs_review BOOLEAN DEFAULT 0,
+    review_reasons TEXT,        -- comma-separated, see the review package; NULL when none
+    reviewed_at TIMESTAMP,      -- when the extraction was approved
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
@@ -79,6 +119,130 @@ CREATE TABLE IF NOT EXISTS job_skills (
     FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
 );
 
+CREATE TABLE IF NOT EXISTS tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS job_tags (
+    job_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_id, tag_id),
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS contacts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    role TEXT,               -- recruiter, hiring_manager, referrer, interviewer, other
+    email TEXT,
+    linkedin_url TEXT,
+    company TEXT,
+    notes TEXT,
+    last_contacted TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS job_contacts (
+    job_id INTEGER NOT NULL,
+    contact_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_id, contact_id),
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (contact_id) REFERENCES contacts(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS interviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL,
+    round_type TEXT NOT NULL,   -- phone_screen, technical, take_home, system_design, onsite, ...
+    scheduled_at TIMESTAMP,
+    duration_minutes INTEGER,
+    interviewers TEXT,          -- JSON array of names
+    outcome TEXT DEFAULT 'pending', -- pending, passed, failed, cancelled
+    feedback TEXT,
+    revision INTEGER DEFAULT 0, -- bumped on every update, the iCalendar SEQUENCE
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS job_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,         -- follow_up, deadline, closing_date, other
+    title TEXT,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    all_day BOOLEAN DEFAULT 0,
+    notes TEXT,
+    revision INTEGER DEFAULT 0, -- bumped on every update, the iCalendar SEQUENCE
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS reminder_state (
+    job_id INTEGER NOT NULL,
+    rule TEXT NOT NULL,
+    snoozed_until TIMESTAMP,
+    dismissed_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_id, rule),
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS extraction_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER,             -- NULL when no job was saved; kept when the job is deleted
+    kind TEXT NOT NULL,         -- extract, reextract, compare
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    prompt_version TEXT,
+    prompt_tokens INTEGER DEFAULT 0,
+    completion_tokens INTEGER DEFAULT 0,
+    latency_ms INTEGER DEFAULT 0,
+    cost_usd REAL,              -- NULL when the model has no configured price
+    error TEXT,                 -- NULL on success
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS extraction_cache (
+    key TEXT PRIMARY KEY,       -- sha256 of normalized text, provider, model, prompt version, known fields
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    job_json TEXT NOT NULL,
+    hits INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_hit_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS job_field_checks (
+    job_id INTEGER NOT NULL,
+    field TEXT NOT NULL,        -- dotted JSON path, e.g. compensation.salary_min
+    value TEXT NOT NULL,        -- one element for list fields
+    grounded BOOLEAN NOT NULL,
+    confidence REAL NOT NULL,
+    checked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_id, field, value),
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS job_evidence (
+    job_id INTEGER NOT NULL,
+    field TEXT NOT NULL,        -- key of the extraction's evidence object, e.g. salary
+    quote TEXT NOT NULL,
+    start_offset INTEGER,       -- rune offsets into jobs.raw_text; NULL when the quote wasn't found
+    end_offset INTEGER,
+    PRIMARY KEY (job_id, field),
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
 CREATE INDEX IF NOT EXISTS idx_company ON jobs(company_name);
 CREATE INDEX IF NOT EXISTS idx_status ON jobs(status);
 CREATE INDEX IF NOT EXISTS idx_workplace_type ON jobs(workplace_type);
@@ -88,4 +252,9 @@ CREATE INDEX IF NOT EXISTS idx_extracted_at ON jobs(extracted_at DESC);
 
 CREATE INDEX IF NOT EXISTS idx_job_skills_name ON job_skills(skill_name);
 CREATE INDEX IF NOT EXISTS idx_job_skills_category ON job_skills(skill_category);
+CREATE INDEX IF NOT EXISTS idx_job_tags_tag ON job_tags(tag_id);
+CREATE INDEX IF NOT EXISTS idx_job_contacts_contact ON job_contacts(contact_id);
+CREATE INDEX IF NOT EXISTS idx_interviews_job ON interviews(job_id);
+CREATE INDEX IF NOT EXISTS idx_job_events_job ON job_events(job_id);
+CREATE INDEX IF NOT EXISTS idx_extraction_runs_created ON extraction_runs(created_at);
 `
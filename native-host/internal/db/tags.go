@@ -0,0 +1,131 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// TagSummary is a tag with the number of jobs carrying it.
+type TagSummary struct {
+	Name  string
+	Count int
+}
+
+// NormalizeTag trims a tag name and rejects values that can't be stored.
+// Commas are reserved because ListJobs returns tags comma-joined.
+func NormalizeTag(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("tag name is empty")
+	}
+	if strings.Contains(name, ",") {
+		return "", fmt.Errorf("tag name cannot contain commas")
+	}
+	return name, nil
+}
+
+// ListTags returns every tag, including unused ones, with its job count.
+func (db *DB) ListTags() ([]TagSummary, error) {
+	query := `
+        SELECT t.name, COUNT(jt.job_id) AS cnt
+        FROM tags t
+        LEFT JOIN job_tags jt ON jt.tag_id = t.id
+        GROUP BY t.id, t.name
+        ORDER BY cnt DESC, t.name ASC
+    `
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []TagSummary
+	for rows.Next() {
+		var t TagSummary
+		if err := rows.Scan(&t.Name, &t.Count); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
+
+// GetJobTags returns the tags attached to a job.
+func (db *DB) GetJobTags(jobID int64) ([]string, error) {
+	query := `
+        SELECT t.name
+        FROM job_tags jt
+        JOIN tags t ON t.id = jt.tag_id
+        WHERE jt.job_id = ?
+        ORDER BY t.name
+    `
+	rows, err := db.Query(query, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tags = append(tags, name)
+	}
+	return tags, rows.Err()
+}
+
+// AddJobTag attaches a tag to a job, creating the tag if needed. It
+// returns sql.ErrNoRows, and creates nothing, when the job doesn't exist.
+func (db *DB) AddJobTag(jobID int64, name string) error {
+	name, err := NormalizeTag(name)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM jobs WHERE id = ?)`, jobID).Scan(&exists); err != nil {
+		return fmt.Errorf("lookup job: %w", err)
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec(`INSERT INTO tags (name) VALUES (?) ON CONFLICT(name) DO NOTHING`, name); err != nil {
+		return fmt.Errorf("insert tag: %w", err)
+	}
+
+	var tagID int64
+	if err := tx.QueryRow(`SELECT id FROM tags WHERE name = ?`, name).Scan(&tagID); err != nil {
+		return fmt.Errorf("lookup tag: %w", err)
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO job_tags (job_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING`,
+		jobID, tagID,
+	); err != nil {
+		return fmt.Errorf("link tag: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// RemoveJobTag detaches a tag from a job. The tag itself is kept so it
+// stays available in ListTags.
+func (db *DB) RemoveJobTag(jobID int64, name string) error {
+	query := `
+        DELETE FROM job_tags
+        WHERE job_id = ?
+          AND tag_id IN (SELECT id FROM tags WHERE name = ?)
+    `
+	_, err := db.Exec(query, jobID, strings.TrimSpace(name))
+	return err
+}
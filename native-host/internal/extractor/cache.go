@@ -0,0 +1,76 @@
+package extractor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"native-host/internal/models"
+)
+
+// Cache stores extraction results, e.g. in the extraction_cache table.
+type Cache interface {
+	// GetCachedExtraction returns nil on a miss.
+	GetCachedExtraction(key string) (*models.JobPosting, error)
+	PutCachedExtraction(key, provider, model, promptVersion string, job *models.JobPosting) error
+}
+
+// DefaultCache is used by Run. Nil disables caching.
+var DefaultCache Cache
+
+// cacheKey hashes everything that determines an extraction: the posting
+// text with whitespace normalized, the provider, model and resolved prompt
+// version, and the known fields, which are part of the prompt.
+func cacheKey(jobText, provider, model, promptVersion string, known *models.JobPosting) string {
+	h := sha256.New()
+	h.Write([]byte(strings.Join(strings.Fields(jobText), " ")))
+	for _, s := range []string{provider, model, promptVersion} {
+		h.Write([]byte{0})
+		h.Write([]byte(s))
+	}
+	h.Write([]byte{0})
+	if known != nil {
+		_ = json.NewEncoder(h).Encode(known)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedResult looks the extraction up in DefaultCache. It returns nil on a
+// miss, when caching is off or settings.NoCache is set, and when the prompt
+// version can't be resolved (the extraction itself will report why).
+func cachedResult(jobText string, settings models.Settings, known *models.JobPosting) *Result {
+	if DefaultCache == nil || settings.NoCache {
+		return nil
+	}
+	provider, model := providerName(settings), modelName(settings)
+	version, err := ResolvePromptVersion(settings.PromptVersion, provider)
+	if err != nil {
+		return nil
+	}
+
+	job, err := DefaultCache.GetCachedExtraction(cacheKey(jobText, provider, model, version, known))
+	if err != nil {
+		log.Printf("Reading extraction cache: %v", err)
+		return nil
+	}
+	if job == nil {
+		return nil
+	}
+	// The posting is being captured again now, whatever the cached run said.
+	job.ExtractedAt = time.Now().Format("2006-01-02T15:04:05Z07:00")
+	log.Printf("Using cached %s/%s extraction (prompt %s)", provider, model, version)
+	return &Result{Job: job, Provider: provider, Model: model, PromptVersion: version, Cached: true}
+}
+
+func storeResult(jobText string, known *models.JobPosting, res *Result) {
+	if DefaultCache == nil {
+		return
+	}
+	key := cacheKey(jobText, res.Provider, res.Model, res.PromptVersion, known)
+	if err := DefaultCache.PutCachedExtraction(key, res.Provider, res.Model, res.PromptVersion, res.Job); err != nil {
+		log.Printf("Writing extraction cache: %v", err)
+	}
+}
@@ -0,0 +1,346 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"native-host/internal/injection"
+	"native-host/internal/langdetect"
+	"native-host/internal/models"
+	"native-host/internal/preprocess"
+	"native-host/internal/redact"
+	"native-host/pkg/utils"
+)
+
+// Usage is the token count a provider reported for one call.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Result is an extracted posting together with how it was obtained.
+type Result struct {
+	Job           *models.JobPosting
+	Provider      string
+	Model         string
+	PromptVersion string
+	FallbackFrom  string        // provider whose exceeded budget made Run use the fallback model
+	Cached        bool          // served from DefaultCache, no request was made
+	Chunks        int           // provider calls the posting was split into
+	Usage         Usage         // summed over the chunks
+	Latency       time.Duration // the provider calls, from request to response body
+}
+
+// MaxPostingChars is how much posting text goes into one prompt, in runes.
+// Longer postings are shortened to at most maxChunks times that and
+// extracted chunk by chunk. 0 sends every posting whole.
+var MaxPostingChars int
+
+// maxChunks bounds the provider calls spent on one posting.
+const maxChunks = 4
+
+// RedactTerms are personal strings, such as the user's name, removed from
+// the text sent to remote providers along with emails and phone numbers.
+var RedactTerms []string
+
+// Extract runs the provider selected in settings and returns the posting.
+// See Run.
+func Extract(jobText string, settings models.Settings, known *models.JobPosting) (*models.JobPosting, error) {
+	res, err := Run(jobText, settings, known)
+	if err != nil {
+		return nil, err
+	}
+	return res.Job, nil
+}
+
+// Run runs the provider selected in settings. Anything other than
+// "perplexity" goes to the local Ollama model, as the extension expects.
+// Results are looked up in DefaultCache first unless settings.NoCache, and
+// stored there after every successful call. DefaultGuard is consulted
+// before a call; a provider over budget is replaced by its fallback model
+// when one is configured.
+//
+// The text is cleaned of page chrome first (see the preprocess package).
+// Postings longer than MaxPostingChars are extracted chunk by chunk and the
+// partial results merged, earlier chunks winning.
+//
+// known holds fields already parsed deterministically from the page (nil
+// when there are none). They are passed to the model as context and then
+// override whatever it returned.
+//
+// On failure the Result is still returned, without a Job, describing the
+// attempt (provider, model, latency and any reported usage) for accounting.
+func Run(jobText string, settings models.Settings, known *models.JobPosting) (*Result, error) {
+	if res := cachedResult(jobText, settings, known); res != nil {
+		return res, nil
+	}
+
+	provider := providerName(settings)
+	fallbackFrom := ""
+	if err := DefaultGuard.Allow(provider, modelName(settings)); err != nil {
+		fb, ok := DefaultGuard.fallback(settings)
+		if !ok || !errors.Is(err, ErrBudgetExceeded) {
+			return nil, err
+		}
+		log.Printf("%v; falling back to ollama/%s", err, fb.OllamaModel)
+		settings, fallbackFrom = fb, provider
+		if res := cachedResult(jobText, settings, known); res != nil {
+			res.FallbackFrom = fallbackFrom
+			return res, nil
+		}
+		if err := DefaultGuard.Allow(providerName(settings), modelName(settings)); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := extractChunks(chunkText(jobText), settings, known)
+	res.FallbackFrom = fallbackFrom
+	if err != nil {
+		return res, err
+	}
+
+	if paths := res.Job.Merge(known); len(paths) > 0 {
+		log.Printf("Kept %d fields from structured data: %v", len(paths), paths)
+	}
+
+	// Prompts from v2 on no longer ask the model to echo these back.
+	if res.Job.ExtractedAt == "" {
+		res.Job.ExtractedAt = time.Now().Format("2006-01-02T15:04:05Z07:00")
+	}
+	if res.Job.SourceURL == "" {
+		res.Job.SourceURL = utils.ExtractURL(jobText)
+	}
+	res.Job.PromptVersion = res.PromptVersion
+	res.Job.PostingLanguage = langdetect.Detect(jobText)
+	if res.Job.InjectionFlags = scanForInjection(jobText, known); len(res.Job.InjectionFlags) > 0 {
+		log.Printf("Posting contains text aimed at the model (%v); flagging the job", res.Job.InjectionFlags)
+	}
+
+	storeResult(jobText, known, res)
+	return res, nil
+}
+
+// chunkText cleans jobText and splits it into the pieces sent to the
+// provider, usually just one.
+func chunkText(jobText string) []string {
+	text := preprocess.Clean(jobText)
+	if removed := len(jobText) - len(text); removed > 0 {
+		log.Printf("Removed %d bytes of page boilerplate", removed)
+	}
+	if MaxPostingChars <= 0 || utf8.RuneCountInString(text) <= MaxPostingChars {
+		return []string{text}
+	}
+
+	// Chunks after the first repeat up to a quarter chunk of lead text.
+	budget := MaxPostingChars*maxChunks - (maxChunks-1)*MaxPostingChars/4
+	shortened := preprocess.Shorten(text, budget)
+	if len(shortened) < len(text) {
+		log.Printf("Posting too long, shortened from %d to %d bytes", len(text), len(shortened))
+	}
+	chunks := preprocess.Chunk(shortened, MaxPostingChars)
+	if len(chunks) > maxChunks {
+		log.Printf("Dropping the last %d of %d chunks", len(chunks)-maxChunks, len(chunks))
+		chunks = chunks[:maxChunks]
+	}
+	log.Printf("Extracting posting in %d chunks", len(chunks))
+	return chunks
+}
+
+// extractChunks extracts each chunk and merges the results into the first.
+// Every call after the first is cleared with DefaultGuard again.
+func extractChunks(chunks []string, settings models.Settings, known *models.JobPosting) (*Result, error) {
+	var total *Result
+	for i, chunk := range chunks {
+		if i > 0 {
+			if err := DefaultGuard.Allow(providerName(settings), modelName(settings)); err != nil {
+				total.Job = nil
+				return total, err
+			}
+		}
+
+		res, err := extractRedacted(chunk, settings, known)
+		res.Chunks = i + 1
+
+		if total == nil {
+			total = res
+		} else {
+			total.PromptVersion = res.PromptVersion
+			total.Chunks = res.Chunks
+			total.Usage.PromptTokens += res.Usage.PromptTokens
+			total.Usage.CompletionTokens += res.Usage.CompletionTokens
+			total.Latency += res.Latency
+			total.Job.Absorb(res.Job)
+		}
+		if err != nil {
+			total.Job = nil
+			if len(chunks) > 1 {
+				err = fmt.Errorf("chunk %d of %d: %w", i+1, len(chunks), err)
+			}
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// scanForInjection checks the posting text and the string values of the
+// structured data, which come from the same page.
+func scanForInjection(jobText string, known *models.JobPosting) []string {
+	texts := []string{jobText}
+	for _, v := range known.SetFields() {
+		switch v := v.(type) {
+		case string:
+			texts = append(texts, v)
+		case []string:
+			texts = append(texts, v...)
+		}
+	}
+	return injection.Scan(texts...)
+}
+
+// extractRedacted calls the provider, with personal data replaced by
+// placeholders when the provider is remote: in the text, which the prompt
+// also takes the source URL from, and in the known fields. The placeholders
+// are logged with what they stand for, which never leaves the machine, and
+// put back wherever the model copied them into the result.
+func extractRedacted(text string, settings models.Settings, known *models.JobPosting) (*Result, error) {
+	var m redact.Map
+	if isRemote(settings) {
+		r := redact.New(RedactTerms)
+		text = r.Text(text)
+		var err error
+		if known, err = redactKnown(known, r); err != nil {
+			return &Result{Provider: providerName(settings), Model: modelName(settings)}, err
+		}
+		if m = r.Map(); len(m) > 0 {
+			log.Printf("Redacted %d values before sending to %s: %s", len(m), providerName(settings), m)
+		}
+	}
+
+	var (
+		res *Result
+		err error
+	)
+	if providerName(settings) == "perplexity" {
+		res, err = ExtractWithPerplexity(text, settings, known)
+	} else {
+		res, err = ExtractWithOllama(text, settings, known)
+	}
+	if err == nil && len(m) > 0 {
+		err = restoreRedacted(res.Job, m)
+	}
+	return res, err
+}
+
+// redactKnown returns a copy of known with every string redacted by r.
+func redactKnown(known *models.JobPosting, r *redact.Redactor) (*models.JobPosting, error) {
+	if known == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(known)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	if data, err = json.Marshal(redactStrings(v, r)); err != nil {
+		return nil, err
+	}
+	var redacted models.JobPosting
+	if err := json.Unmarshal(data, &redacted); err != nil {
+		return nil, fmt.Errorf("redact known fields: %w", err)
+	}
+	return &redacted, nil
+}
+
+// redactStrings redacts the strings in decoded JSON, leaving keys alone.
+func redactStrings(v any, r *redact.Redactor) any {
+	switch v := v.(type) {
+	case string:
+		return r.Text(v)
+	case []any:
+		for i := range v {
+			v[i] = redactStrings(v[i], r)
+		}
+	case map[string]any:
+		// In key order, so that placeholders are numbered the same way on
+		// every run.
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v[k] = redactStrings(v[k], r)
+		}
+	}
+	return v
+}
+
+// restoreRedacted replaces placeholders in every string of job.
+func restoreRedacted(job *models.JobPosting, m redact.Map) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	escaped := redact.Map{}
+	for placeholder, orig := range m {
+		quoted, _ := json.Marshal(orig)
+		escaped[placeholder] = string(quoted[1 : len(quoted)-1])
+	}
+	var restored models.JobPosting
+	if err := json.Unmarshal([]byte(escaped.Restore(string(data))), &restored); err != nil {
+		return fmt.Errorf("restore redacted values: %w", err)
+	}
+	*job = restored
+	return nil
+}
+
+// isRemote reports whether settings send the text off this machine: always
+// for Perplexity, and for Ollama when it isn't reached over loopback.
+func isRemote(settings models.Settings) bool {
+	if providerName(settings) == "perplexity" {
+		return true
+	}
+	if settings.OllamaURL == "" {
+		return false
+	}
+	u, err := url.Parse(settings.OllamaURL)
+	if err != nil {
+		return true
+	}
+	if u.Hostname() == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip == nil || !ip.IsLoopback()
+}
+
+func providerName(settings models.Settings) string {
+	if settings.Provider == "perplexity" {
+		return "perplexity"
+	}
+	return "ollama"
+}
+
+// modelName is the model the provider in settings will be asked for.
+func modelName(settings models.Settings) string {
+	if providerName(settings) == "perplexity" {
+		if settings.PerplexityModel != "" {
+			return settings.PerplexityModel
+		}
+		return defaultPerplexityModel
+	}
+	if settings.OllamaModel != "" {
+		return settings.OllamaModel
+	}
+	return defaultOllamaModel
+}
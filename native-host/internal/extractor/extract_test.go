@@ -0,0 +1,60 @@
+package extractor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"native-host/internal/models"
+)
+
+// TestExtractRedactedKnownFields checks that personal data in the known
+// fields doesn't reach a remote provider, and that it shares placeholders
+// with the posting text.
+func TestExtractRedactedKnownFields(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		content, _ := json.Marshal(`{"role_details": {"summary": "Ask [REDACTED_EMAIL_1] or see [REDACTED_PROFILE_1]"}}`)
+		fmt.Fprintf(w, `{"choices": [{"message": {"content": %s}}]}`, content)
+	}))
+	t.Cleanup(srv.Close)
+
+	known := &models.JobPosting{SourceURL: "https://www.linkedin.com/in/jane-doe/"}
+	known.CompanyInfo.CompanyName = "Acme"
+	known.RoleDetails.Summary = "Questions? Write to jane.doe@example.com."
+	text := "Senior Go Engineer at Acme. Contact: Jane.Doe@example.com"
+
+	settings := models.Settings{Provider: "perplexity", PerplexityKey: "test", PerplexityURL: srv.URL}
+	res, err := extractRedacted(text, settings, known)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"jane.doe@example.com", "Jane.Doe@example.com", "linkedin.com/in/jane-doe"} {
+		if strings.Contains(body, s) {
+			t.Errorf("request contains %q", s)
+		}
+	}
+	for _, s := range []string{"[REDACTED_EMAIL_1]", "[REDACTED_PROFILE_1]", "Acme"} {
+		if !strings.Contains(body, s) {
+			t.Errorf("request lacks %q", s)
+		}
+	}
+	if strings.Contains(body, "[REDACTED_EMAIL_2]") {
+		t.Error("the same email got two placeholders")
+	}
+
+	want := "Ask Jane.Doe@example.com or see https://www.linkedin.com/in/jane-doe/"
+	if got := res.Job.RoleDetails.Summary; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+	if known.RoleDetails.Summary != "Questions? Write to jane.doe@example.com." {
+		t.Errorf("known was modified: %q", known.RoleDetails.Summary)
+	}
+}
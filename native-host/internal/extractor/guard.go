@@ -0,0 +1,169 @@
+package extractor
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/models"
+)
+
+// ErrBudgetExceeded is returned, before any request is sent, when a
+// provider has used up its daily or monthly budget.
+var ErrBudgetExceeded = errors.New("budget_exceeded")
+
+// Ledger reports past usage, e.g. from the extraction_runs table.
+type Ledger interface {
+	// ProviderSpend is the estimated cost of provider's runs since t.
+	ProviderSpend(provider string, since time.Time) (float64, error)
+	// ProviderRequests counts provider's runs since t.
+	ProviderRequests(provider string, since time.Time) (int, error)
+}
+
+// Guard enforces the configured budgets and request rates before each
+// call. Days and months are counted in UTC, like the extraction stats.
+//
+// Spending is checked against what was recorded before the call, so a
+// budget can be overrun by the calls already in flight when it runs out.
+type Guard struct {
+	budgets map[string]config.Budget
+	// fallbackModel is the Ollama model used when a budget is exceeded;
+	// empty to fail with ErrBudgetExceeded instead.
+	fallbackModel string
+	ledger        Ledger
+	// price looks up what a model costs, see config.Config.Price.
+	price func(provider, model string) (config.ModelPrice, bool)
+
+	now   func() time.Time
+	sleep func(time.Duration)
+
+	mu     sync.Mutex
+	starts map[string][]time.Time // this process's requests in the last minute
+}
+
+// DefaultGuard is applied by Run. Nil disables all limits.
+var DefaultGuard *Guard
+
+// NewGuard returns a Guard for the given budgets, keyed by provider.
+// ledger may be nil, in which case providers with a spending budget are
+// refused since their spending cannot be checked. So are models price
+// knows no price for, whose runs are recorded without a cost.
+func NewGuard(budgets map[string]config.Budget, fallbackModel string, ledger Ledger, price func(provider, model string) (config.ModelPrice, bool)) *Guard {
+	return &Guard{
+		budgets:       budgets,
+		fallbackModel: fallbackModel,
+		ledger:        ledger,
+		price:         price,
+		now:           time.Now,
+		sleep:         time.Sleep,
+		starts:        map[string][]time.Time{},
+	}
+}
+
+// Allow is called before a request for model to provider. It waits while
+// the provider is at its requests-per-minute limit and returns an error
+// wrapping ErrBudgetExceeded once its budget is spent.
+func (g *Guard) Allow(provider, model string) error {
+	if g == nil {
+		return nil
+	}
+	b, ok := g.budgets[provider]
+	if !ok {
+		return nil
+	}
+
+	if err := g.checkBudget(provider, model, b, g.now().UTC()); err != nil {
+		return err
+	}
+	if b.RequestsPerMinute > 0 {
+		g.waitForSlot(provider, b.RequestsPerMinute)
+	}
+	return nil
+}
+
+func (g *Guard) checkBudget(provider, model string, b config.Budget, now time.Time) error {
+	if b.Daily <= 0 && b.Monthly <= 0 {
+		return nil
+	}
+	if g.ledger == nil {
+		return fmt.Errorf("%w: cannot check %s spending without the database", ErrBudgetExceeded, provider)
+	}
+	if g.price == nil {
+		return fmt.Errorf("%w: cannot check %s spending without prices", ErrBudgetExceeded, provider)
+	}
+	if _, ok := g.price(provider, model); !ok {
+		return fmt.Errorf("%w: %s/%s has no configured price, so its spending can't be counted", ErrBudgetExceeded, provider, model)
+	}
+
+	limits := []struct {
+		name  string
+		limit float64
+		since time.Time
+	}{
+		{"daily", b.Daily, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)},
+		{"monthly", b.Monthly, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, l := range limits {
+		if l.limit <= 0 {
+			continue
+		}
+		spent, err := g.ledger.ProviderSpend(provider, l.since)
+		if err != nil {
+			return fmt.Errorf("check %s spending: %w", provider, err)
+		}
+		if spent >= l.limit {
+			return fmt.Errorf("%w: %s has spent $%.2f of its $%.2f %s budget", ErrBudgetExceeded, provider, spent, l.limit, l.name)
+		}
+	}
+	return nil
+}
+
+// waitForSlot blocks until fewer than rpm requests were made to provider
+// in the last minute, counting both the recorded runs of every host
+// process and the requests this process has started.
+func (g *Guard) waitForSlot(provider string, rpm int) {
+	for {
+		now := g.now()
+		recorded := 0
+		if g.ledger != nil {
+			n, err := g.ledger.ProviderRequests(provider, now.Add(-time.Minute).UTC())
+			if err != nil {
+				log.Printf("Counting %s requests: %v", provider, err)
+			}
+			recorded = n
+		}
+
+		g.mu.Lock()
+		recent := g.starts[provider][:0]
+		for _, t := range g.starts[provider] {
+			if now.Sub(t) < time.Minute {
+				recent = append(recent, t)
+			}
+		}
+		g.starts[provider] = recent
+		if max(recorded, len(recent)) < rpm {
+			g.starts[provider] = append(recent, now)
+			g.mu.Unlock()
+			return
+		}
+		g.mu.Unlock()
+
+		wait := time.Minute / time.Duration(rpm)
+		log.Printf("%s is at %d requests per minute, waiting %s", provider, rpm, wait)
+		g.sleep(wait)
+	}
+}
+
+// fallback returns the settings to use instead of a provider whose budget
+// is exceeded, if a fallback model is configured.
+func (g *Guard) fallback(settings models.Settings) (models.Settings, bool) {
+	if g == nil || g.fallbackModel == "" {
+		return settings, false
+	}
+	settings.Provider = "ollama"
+	settings.OllamaModel = g.fallbackModel
+	return settings, true
+}
@@ -0,0 +1,161 @@
+package extractor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/models"
+)
+
+// fakeLedger records spending per provider at fixed times.
+type fakeLedger struct {
+	spends   map[string][]spend
+	requests map[string][]time.Time
+}
+
+type spend struct {
+	at   time.Time
+	cost float64
+}
+
+func (l *fakeLedger) ProviderSpend(provider string, since time.Time) (float64, error) {
+	total := 0.0
+	for _, s := range l.spends[provider] {
+		if !s.at.Before(since) {
+			total += s.cost
+		}
+	}
+	return total, nil
+}
+
+func (l *fakeLedger) ProviderRequests(provider string, since time.Time) (int, error) {
+	n := 0
+	for _, t := range l.requests[provider] {
+		if !t.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+var testConfig = &config.Config{Prices: []config.ModelPrice{
+	{Provider: "ollama"},
+	{Provider: "perplexity", Model: "sonar", InputPerMillion: 1, OutputPerMillion: 1},
+}}
+
+// newTestGuard returns a Guard whose clock stands at now and only moves
+// when it sleeps.
+func newTestGuard(budgets map[string]config.Budget, fallback string, ledger Ledger, now time.Time) (*Guard, *[]time.Duration) {
+	g := NewGuard(budgets, fallback, ledger, testConfig.Price)
+	var slept []time.Duration
+	g.now = func() time.Time { return now }
+	g.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		now = now.Add(d)
+	}
+	return g, &slept
+}
+
+func TestGuardSpendingLimits(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	ledger := &fakeLedger{spends: map[string][]spend{"perplexity": {
+		{time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC), 50},  // last month
+		{time.Date(2026, 10, 3, 10, 0, 0, 0, time.UTC), 6},   // this month
+		{time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC), 1.5}, // today
+	}}}
+
+	for _, tc := range []struct {
+		name   string
+		budget config.Budget
+		model  string
+		want   bool // allowed
+	}{
+		{"no limits", config.Budget{}, "sonar", true},
+		{"under daily", config.Budget{Daily: 2}, "sonar", true},
+		{"daily spent", config.Budget{Daily: 1.5}, "sonar", false},
+		{"under monthly", config.Budget{Monthly: 10}, "sonar", true},
+		{"monthly spent", config.Budget{Daily: 5, Monthly: 7.5}, "sonar", false},
+		{"unpriced model", config.Budget{Monthly: 100}, "sonar-deep-research", false},
+		{"unpriced model, no spending limit", config.Budget{RequestsPerMinute: 100}, "sonar-deep-research", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g, _ := newTestGuard(map[string]config.Budget{"perplexity": tc.budget}, "", ledger, now)
+			err := g.Allow("perplexity", tc.model)
+			if tc.want && err != nil {
+				t.Errorf("Allow = %v, want nil", err)
+			}
+			if !tc.want && !errors.Is(err, ErrBudgetExceeded) {
+				t.Errorf("Allow = %v, want ErrBudgetExceeded", err)
+			}
+		})
+	}
+}
+
+func TestGuardWithoutLedger(t *testing.T) {
+	g := NewGuard(map[string]config.Budget{"perplexity": {Daily: 1}}, "", nil, testConfig.Price)
+	if err := g.Allow("perplexity", "sonar"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Allow = %v, want ErrBudgetExceeded", err)
+	}
+	if err := g.Allow("ollama", "llama3"); err != nil {
+		t.Errorf("Allow for a provider without budget = %v", err)
+	}
+}
+
+func TestGuardFallback(t *testing.T) {
+	settings := models.Settings{Provider: "perplexity", PerplexityModel: "sonar-pro"}
+
+	var noGuard *Guard
+	if _, ok := noGuard.fallback(settings); ok {
+		t.Error("nil Guard has a fallback")
+	}
+	g := NewGuard(nil, "", &fakeLedger{}, testConfig.Price)
+	if _, ok := g.fallback(settings); ok {
+		t.Error("Guard without fallback model has a fallback")
+	}
+
+	g = NewGuard(nil, "llama3.1:8b", &fakeLedger{}, testConfig.Price)
+	fb, ok := g.fallback(settings)
+	if !ok || providerName(fb) != "ollama" || modelName(fb) != "llama3.1:8b" {
+		t.Errorf("fallback = %s/%s, %v; want ollama/llama3.1:8b, true", providerName(fb), modelName(fb), ok)
+	}
+	if err := g.Allow(providerName(fb), modelName(fb)); err != nil {
+		t.Errorf("Allow for the fallback = %v", err)
+	}
+}
+
+func TestGuardRequestsPerMinute(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	// Another host process made two requests in the last minute.
+	ledger := &fakeLedger{requests: map[string][]time.Time{"perplexity": {
+		now.Add(-50 * time.Second),
+		now.Add(-40 * time.Second),
+	}}}
+	g, slept := newTestGuard(map[string]config.Budget{"perplexity": {RequestsPerMinute: 2}}, "", ledger, now)
+
+	waits := func() time.Duration {
+		var total time.Duration
+		for _, d := range *slept {
+			if d != 30*time.Second {
+				t.Errorf("slept %v, want steps of 30s", d)
+			}
+			total += d
+		}
+		*slept = nil
+		return total
+	}
+
+	for i, want := range []time.Duration{
+		30 * time.Second, // until the recorded requests are a minute old
+		0,                // the second slot of that minute
+		time.Minute,      // until this process's two requests are a minute old
+	} {
+		if err := g.Allow("perplexity", "sonar"); err != nil {
+			t.Fatal(err)
+		}
+		if got := waits(); got != want {
+			t.Errorf("request %d waited %v, want %v", i+1, got, want)
+		}
+	}
+}
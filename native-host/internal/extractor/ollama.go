@@ -9,59 +9,85 @@ import (
 	"native-host/internal/models"
 	"native-host/pkg/utils"
 	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOllamaURL   = "http://localhost:11434"
+	defaultOllamaModel = "qwen2.5:7b"
 )
 
 type ollamaRequest struct {
 	Model  string `json:"model"`
+	System string `json:"system,omitempty"` // replaces the model's default system prompt
 	Prompt string `json:"prompt"`
 	Stream bool   `json:"stream"`
 	Format string `json:"format"`
 }
 
 type ollamaResponse struct {
-	Response string `json:"response"`
-	Done     bool   `json:"done"`
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
 }
 
-func ExtractWithOllama(jobText string, settings models.Settings) (*models.JobPosting, error) {
-	sourceURL := utils.ExtractURL(jobText)
-	prompt := BuildPrompt(jobText, sourceURL)
+// ExtractWithOllama runs the prompt against the local Ollama model. The
+// Result is returned with the error once the model is known, so that
+// failed attempts can be accounted for; see Run.
+func ExtractWithOllama(jobText string, settings models.Settings, known *models.JobPosting) (*Result, error) {
+	model := modelName(settings)
+	res := &Result{Provider: "ollama", Model: model, PromptVersion: settings.PromptVersion}
 
-	model := settings.OllamaModel
-	if model == "" {
-		model = "qwen2.5:7b"
+	sourceURL := utils.ExtractURL(jobText)
+	prompt, err := BuildPrompt(jobText, sourceURL, known, settings.PromptVersion, "ollama")
+	if err != nil {
+		return res, err
 	}
+	res.PromptVersion = prompt.Version
 
 	reqBody := ollamaRequest{
 		Model:  model,
-		Prompt: prompt,
+		System: prompt.System,
+		Prompt: prompt.User,
 		Stream: false,
 		Format: "json",
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
+		return res, fmt.Errorf("marshal request: %w", err)
 	}
 
-	resp, err := http.Post("http://localhost:11434/api/generate", "application/json", bytes.NewBuffer(jsonBody))
+	start := time.Now()
+	baseURL := settings.OllamaURL
+	if baseURL == "" {
+		baseURL = defaultOllamaURL
+	}
+	resp, err := http.Post(strings.TrimSuffix(baseURL, "/")+"/api/generate", "application/json", bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, fmt.Errorf("call ollama: %w", err)
+		res.Latency = time.Since(start)
+		return res, fmt.Errorf("call ollama: %w", err)
 	}
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(resp.Body)
+	res.Latency = time.Since(start)
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ollama returned status %d", resp.StatusCode)
+		return res, fmt.Errorf("ollama returned status %d", resp.StatusCode)
 	}
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return res, fmt.Errorf("read response: %w", err)
 	}
 
 	var ollamaResp ollamaResponse
 	if err := json.Unmarshal(body, &ollamaResp); err != nil {
-		return nil, fmt.Errorf("parse ollama response: %w", err)
+		return res, fmt.Errorf("parse ollama response: %w", err)
+	}
+	res.Usage = Usage{
+		PromptTokens:     ollamaResp.PromptEvalCount,
+		CompletionTokens: ollamaResp.EvalCount,
 	}
 
 	log.Printf("Ollama response length: %d bytes", len(ollamaResp.Response))
@@ -71,8 +97,9 @@ func ExtractWithOllama(jobText string, settings models.Settings) (*models.JobPos
 	var jobPosting models.JobPosting
 	if err := json.Unmarshal([]byte(jsonStr), &jobPosting); err != nil {
 		log.Printf("Failed to parse JSON. Response was: %s", jsonStr)
-		return nil, fmt.Errorf("parse job data: %w", err)
+		return res, fmt.Errorf("parse job data: %w", err)
 	}
 
-	return &jobPosting, nil
+	res.Job = &jobPosting
+	return res, nil
 }
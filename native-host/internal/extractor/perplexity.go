@@ -7,12 +7,18 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"native-host/internal/models"
 	"native-host/pkg/utils"
 )
 
+const (
+	defaultPerplexityURL   = "https://api.perplexity.ai"
+	defaultPerplexityModel = "sonar-pro"
+)
+
 type perplexityRequest struct {
 	Model    string              `json:"model"`
 	Messages []perplexityMessage `json:"messages"`
@@ -29,59 +35,76 @@ type perplexityResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
 }
 
-func ExtractWithPerplexity(jobText string, settings models.Settings) (*models.JobPosting, error) {
-	sourceURL := utils.ExtractURL(jobText)
-	prompt := BuildPrompt(jobText, sourceURL)
+// ExtractWithPerplexity runs the prompt against the Perplexity API. Like
+// ExtractWithOllama, it returns the Result along with most errors.
+func ExtractWithPerplexity(jobText string, settings models.Settings, known *models.JobPosting) (*Result, error) {
+	model := modelName(settings)
+	res := &Result{Provider: "perplexity", Model: model, PromptVersion: settings.PromptVersion}
 
-	model := settings.PerplexityModel
-	if model == "" {
-		model = "sonar-pro"
+	sourceURL := utils.ExtractURL(jobText)
+	prompt, err := BuildPrompt(jobText, sourceURL, known, settings.PromptVersion, "perplexity")
+	if err != nil {
+		return res, err
 	}
+	res.PromptVersion = prompt.Version
 
-	reqBody := perplexityRequest{
-		Model: model,
-		Messages: []perplexityMessage{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
+	reqBody := perplexityRequest{Model: model}
+	if prompt.System != "" {
+		reqBody.Messages = append(reqBody.Messages, perplexityMessage{Role: "system", Content: prompt.System})
 	}
+	reqBody.Messages = append(reqBody.Messages, perplexityMessage{Role: "user", Content: prompt.User})
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
+		return res, fmt.Errorf("marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", "https://api.perplexity.ai/chat/completions", bytes.NewBuffer(jsonBody))
+	baseURL := settings.PerplexityURL
+	if baseURL == "" {
+		baseURL = defaultPerplexityURL
+	}
+	req, err := http.NewRequest("POST", strings.TrimSuffix(baseURL, "/")+"/chat/completions", bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return nil, err
+		return res, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+settings.PerplexityKey)
 
 	client := &http.Client{Timeout: 60 * time.Second}
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("call perplexity: %w", err)
+		res.Latency = time.Since(start)
+		return res, fmt.Errorf("call perplexity: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("perplexity returned status %d: %s", resp.StatusCode, string(body))
+		res.Latency = time.Since(start)
+		return res, fmt.Errorf("perplexity returned status %d: %s", resp.StatusCode, string(body))
 	}
 
 	var perplexityResp perplexityResponse
-	if err := json.NewDecoder(resp.Body).Decode(&perplexityResp); err != nil {
-		return nil, fmt.Errorf("parse perplexity response: %w", err)
+	err = json.NewDecoder(resp.Body).Decode(&perplexityResp)
+	res.Latency = time.Since(start)
+	if err != nil {
+		return res, fmt.Errorf("parse perplexity response: %w", err)
+	}
+	res.Usage = Usage{
+		PromptTokens:     perplexityResp.Usage.PromptTokens,
+		CompletionTokens: perplexityResp.Usage.CompletionTokens,
 	}
 
 	if len(perplexityResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from perplexity")
+		return res, fmt.Errorf("no response from perplexity")
 	}
 
 	log.Printf("Perplexity response length: %d bytes", len(perplexityResp.Choices[0].Message.Content))
@@ -91,8 +114,9 @@ func ExtractWithPerplexity(jobText string, settings models.Settings) (*models.Jo
 	var jobPosting models.JobPosting
 	if err := json.Unmarshal([]byte(jsonStr), &jobPosting); err != nil {
 		log.Printf("Failed to parse JSON. Response was: %s", jsonStr)
-		return nil, fmt.Errorf("parse job data: %w", err)
+		return res, fmt.Errorf("parse job data: %w", err)
 	}
 
-	return &jobPosting, nil
+	res.Job = &jobPosting
+	return res, nil
 }
@@ -1,90 +1,272 @@
 package extractor
 
 import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
 	"time"
+
+	"native-host/internal/langdetect"
+	"native-host/internal/models"
 )
 
-func BuildPrompt(jobText, sourceURL string) string {
-	return fmt.Sprintf(`Extract job posting information into structured JSON for analytics. Extract ONLY what is explicitly stated.
-
-Job Posting:
-%s
-
-Return this JSON structure:
-{
-  "metadata": {
-    "job_title": "exact title from posting",
-    "department": "Engineering, Product, Sales, etc.",
-    "seniority_level": "Junior|Mid|Senior|Staff|Principal|Lead",
-    "job_function": "Backend|Frontend|FullStack|DevOps|Data|Mobile|Security|Embedded"
-  },
-  "company_info": {
-    "company_name": "exact company name",
-    "industry": "single primary industry: SaaS, E-commerce, Finance, Healthcare, etc.",
-    "company_size": "10-50, 50-200, 200-1000, 1000+, or empty",
-    "location_full": "full location as stated",
-    "location_city": "extract city name",
-    "location_country": "extract country name or region (e.g., USA, UK, EMEA, Remote)"
-  },
-  "role_details": {
-    "summary": "1-2 sentence role summary",
-    "key_responsibilities": ["extract exact bullet points"],
-    "team_structure": "team info if mentioned"
-  },
-  "requirements": {
-    "years_experience_min": 0,
-    "years_experience_max": 0,
-    "education_level": "None|Bachelor's|Master's|PhD",
-    "requires_specific_degree": false,
-    "technical_skills": {
-      "programming_languages": ["Go", "Python"],
-      "frameworks": ["React", "Django"],
-      "databases": ["PostgreSQL", "Redis"],
-      "cloud_platforms": ["AWS", "GCP", "Azure"],
-      "devops_tools": ["Docker", "Kubernetes", "Terraform"],
-      "other": ["Git", "Linux"]
-    },
-    "soft_skills": ["Communication", "Problem-solving"],
-    "nice_to_have": ["skill or experience that's nice to have"]
-  },
-  "compensation": {
-    "salary_min": 0,
-    "salary_max": 0,
-    "salary_currency": "USD|EUR|GBP|empty",
-    "has_equity": false,
-    "has_remote_stipend": false,
-    "benefits": ["401k", "health insurance"],
-    "offers_visa_sponsorship": false,
-    "offers_health_insurance": false,
-    "offers_pto": false,
-    "offers_professional_development": false,
-    "offers_401k": false
-  },
-  "work_arrangement": {
-    "workplace_type": "Remote|Hybrid|On-site",
-    "job_type": "Full-time|Part-time|Contract|Internship",
-    "is_remote_friendly": true,
-    "timezone_requirements": "EMEA|US|APAC|Flexible|empty"
-  },
-  "market_signals": {
-    "urgency_level": "Standard|Urgent|Immediate",
-    "interview_rounds": 0,
-    "has_take_home": false,
-    "has_pair_programming": false
-  },
-  "extracted_at": "%s",
-  "source_url": "%s"
+// Prompts are text/template files under prompts/<version>/. prompt.tmpl
+// defines the "system" and "user" templates that are sent (an empty system
+// message is left out); prompt.<provider>.tmpl, when present, is parsed on
+// top of it and redefines whichever templates that provider needs
+// differently. Templates receive a promptData, and can call withoutKnown
+// to render a template with the lines of known fields left out.
+//
+// Versions are frozen once released: change a prompt by adding a version,
+// so that jobs extracted with the old one remain identifiable.
+//
+//go:embed prompts
+var embeddedPrompts embed.FS
+
+// DefaultPromptVersion is used when the settings name no version.
+const DefaultPromptVersion = "v5"
+
+// PromptDir holds user overrides laid out like the embedded prompts:
+// <PromptDir>/<version>/prompt[.<provider>].tmpl. Override files are parsed
+// after the embedded ones, so they can redefine single templates of an
+// existing version or add a new version. Empty disables overrides.
+var PromptDir string
+
+// Prompt is a rendered prompt.
+type Prompt struct {
+	// Version is the prompt version, followed by "+<hash>" when override
+	// files changed it, so that edited prompts are told apart.
+	Version string
+	System  string
+	User    string
+}
+
+type promptData struct {
+	JobText     string
+	SourceURL   string
+	ExtractedAt string
+	Language    string // English name of the detected language, "" when unknown
+	Boundary    string // delimits the posting; derived from its text, see boundary
+	Known       []knownField
+}
+
+type knownField struct {
+	Path  string
+	Value string // JSON
+}
+
+// BuildPrompt renders the extraction prompt of the given version (the
+// default when empty) for provider. Fields in known were read from
+// structured data on the page; the model is told to keep them and only
+// fill in the rest.
+func BuildPrompt(jobText, sourceURL string, known *models.JobPosting, version, provider string) (*Prompt, error) {
+	if version == "" {
+		version = DefaultPromptVersion
+	}
+	tmpl, overrides, err := loadPrompt(version, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	data := promptData{
+		JobText:     jobText,
+		SourceURL:   sourceURL,
+		ExtractedAt: time.Now().Format("2006-01-02T15:04:05Z07:00"),
+		Language:    langdetect.Name(langdetect.Detect(jobText)),
+		Boundary:    boundary(jobText),
+		Known:       knownFields(known),
+	}
+
+	p := &Prompt{Version: version}
+	if overrides != "" {
+		p.Version += "+" + overrides
+	}
+	for name, out := range map[string]*string{"system": &p.System, "user": &p.User} {
+		if tmpl.Lookup(name) == nil {
+			continue
+		}
+		var b strings.Builder
+		if err := tmpl.ExecuteTemplate(&b, name, data); err != nil {
+			return nil, fmt.Errorf("prompt %s: %w", version, err)
+		}
+		*out = strings.TrimSpace(b.String())
+	}
+	if p.User == "" {
+		return nil, fmt.Errorf("prompt %s: empty user message", version)
+	}
+	return p, nil
+}
+
+// boundary is a hash of the posting text. A page can't contain the hash of
+// its own text, so it can't close the block the posting is fenced in.
+func boundary(jobText string) string {
+	sum := sha256.Sum256([]byte(jobText))
+	return hex.EncodeToString(sum[:6])
+}
+
+type promptSource struct {
+	fsys     fs.FS
+	override bool
 }
 
-CRITICAL EXTRACTION RULES:
-1. years_experience_min/max: Extract numbers from "3-5 years" → min:3, max:5. If "5+ years" → min:5, max:0
-2. seniority_level: Infer from title (Junior/Mid/Senior/Staff/Principal/Lead)
-3. job_function: Categorize the role type (Backend/Frontend/etc)
-4. salary_min/max: Extract numbers only. "€80k-100k" → min:80000, max:100000
-5. technical_skills: Use simple names only ["Go", "Python"], not full sentences
-6. Boolean fields: Set to true ONLY if explicitly mentioned
-7. urgency_level: "Urgent" if mentions "immediate", "ASAP", "urgent". Otherwise "Standard"
+// loadPrompt parses the files of one prompt version. It also returns a
+// short hash of the override files used, or "" when there were none.
+func loadPrompt(version, provider string) (*template.Template, string, error) {
+	if version != filepath.Base(version) || strings.HasPrefix(version, ".") {
+		return nil, "", fmt.Errorf("invalid prompt version %q", version)
+	}
+
+	embedded, _ := fs.Sub(embeddedPrompts, "prompts")
+	sources := []promptSource{{embedded, false}}
+	if PromptDir != "" {
+		sources = append(sources, promptSource{os.DirFS(PromptDir), true})
+	}
+
+	tmpl := template.New(version)
+	tmpl.Funcs(template.FuncMap{
+		"withoutKnown": func(name string, data promptData) (string, error) {
+			var b strings.Builder
+			if err := tmpl.ExecuteTemplate(&b, name, data); err != nil {
+				return "", err
+			}
+			return withoutKnown(b.String(), data.Known), nil
+		},
+	})
+	hash := sha256.New()
+	found, overridden := false, false
+	for _, name := range []string{"prompt.tmpl", "prompt." + provider + ".tmpl"} {
+		for _, src := range sources {
+			data, err := fs.ReadFile(src.fsys, version+"/"+name)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, "", fmt.Errorf("read prompt: %w", err)
+			}
+			if _, err := tmpl.New(name).Parse(string(data)); err != nil {
+				return nil, "", fmt.Errorf("parse prompt %s/%s: %w", version, name, err)
+			}
+			found = true
+			if src.override {
+				overridden = true
+				fmt.Fprintf(hash, "%s\x00%s\x00", name, data)
+			}
+		}
+	}
+	if !found {
+		return nil, "", fmt.Errorf("unknown prompt version %q (available: %s)", version, strings.Join(PromptVersions(), ", "))
+	}
+
+	if !overridden {
+		return tmpl, "", nil
+	}
+	return tmpl, hex.EncodeToString(hash.Sum(nil))[:8], nil
+}
+
+// ResolvePromptVersion returns the version BuildPrompt would record, with
+// the default filled in and the hash of any override files appended.
+func ResolvePromptVersion(version, provider string) (string, error) {
+	if version == "" {
+		version = DefaultPromptVersion
+	}
+	_, overrides, err := loadPrompt(version, provider)
+	if err != nil {
+		return "", err
+	}
+	if overrides != "" {
+		version += "+" + overrides
+	}
+	return version, nil
+}
+
+// PromptVersions lists the embedded and user-defined prompt versions.
+func PromptVersions() []string {
+	seen := map[string]bool{}
+	add := func(entries []fs.DirEntry) {
+		for _, e := range entries {
+			if e.IsDir() {
+				seen[e.Name()] = true
+			}
+		}
+	}
+	entries, _ := embeddedPrompts.ReadDir("prompts")
+	add(entries)
+	if PromptDir != "" {
+		entries, _ = os.ReadDir(PromptDir)
+		add(entries)
+	}
+
+	versions := make([]string, 0, len(seen))
+	for v := range seen {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func knownFields(known *models.JobPosting) []knownField {
+	fields := known.SetFields()
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	out := make([]knownField, 0, len(paths))
+	for _, path := range paths {
+		var b strings.Builder
+		enc := json.NewEncoder(&b)
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(fields[path])
+		out = append(out, knownField{Path: path, Value: strings.TrimSuffix(b.String(), "\n")})
+	}
+	return out
+}
+
+var structureLineRe = regexp.MustCompile(`^\s*"(\w+)":\s*(.*?),?$`)
+
+// withoutKnown removes the fields in known from a JSON structure written
+// one field per line, as in the prompts, and fixes up the commas. The
+// model needn't spend tokens on values the host fills in anyway.
+func withoutKnown(structure string, known []knownField) string {
+	if len(known) == 0 {
+		return structure
+	}
+	paths := make(map[string]bool, len(known))
+	for _, f := range known {
+		paths[f.Path] = true
+	}
+
+	var kept, objects []string
+	for _, line := range strings.Split(structure, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "}") && len(objects) > 0 {
+			objects = objects[:len(objects)-1]
+		}
+		if m := structureLineRe.FindStringSubmatch(line); m != nil {
+			if m[2] == "{" {
+				objects = append(objects, m[1])
+			} else if paths[strings.Join(append(objects, m[1]), ".")] {
+				continue
+			}
+		}
+		kept = append(kept, strings.TrimSuffix(line, ","))
+	}
 
-Return ONLY valid JSON.`, jobText, time.Now().Format("2006-01-02T15:04:05Z07:00"), sourceURL)
+	for i := range kept[:len(kept)-1] {
+		line, next := strings.TrimSpace(kept[i]), strings.TrimSpace(kept[i+1])
+		if line != "" && !strings.HasSuffix(line, "{") && next != "" && !strings.HasPrefix(next, "}") {
+			kept[i] += ","
+		}
+	}
+	return strings.Join(kept, "\n")
 }
@@ -0,0 +1,126 @@
+package extractor
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"native-host/internal/models"
+)
+
+// TestBuildPromptOmitsKnownFields checks that fields read from the page are
+// listed as known but left out of the JSON structure the model fills in.
+func TestBuildPromptOmitsKnownFields(t *testing.T) {
+	known := &models.JobPosting{}
+	known.Metadata.JobTitle = "Senior Go Engineer"
+	known.CompanyInfo.CompanyName = "Acme"
+	known.Compensation.SalaryMin = 80000
+	known.Requirements.TechnicalSkills.ProgrammingLanguages = []string{"Go"}
+
+	for _, version := range PromptVersions() {
+		t.Run(version, func(t *testing.T) {
+			p, err := BuildPrompt("Senior Go Engineer at Acme.", "https://acme.example/jobs/1", known, version, "ollama")
+			if err != nil {
+				t.Fatal(err)
+			}
+			text := p.System + "\n" + p.User
+
+			structure := jsonStructure(t, text)
+			var fields map[string]any
+			if err := json.Unmarshal([]byte(structure), &fields); err != nil {
+				t.Fatalf("structure is not valid JSON: %v\n%s", err, structure)
+			}
+			for _, path := range []string{
+				"metadata.job_title",
+				"company_info.company_name",
+				"compensation.salary_min",
+				"requirements.technical_skills.programming_languages",
+			} {
+				if hasPath(fields, path) {
+					t.Errorf("structure still has known field %s", path)
+				}
+				if !strings.Contains(text, "- "+path+": ") {
+					t.Errorf("known field %s is not listed", path)
+				}
+			}
+			for _, path := range []string{
+				"metadata.seniority_level",
+				"compensation.salary_max",
+				"requirements.technical_skills.frameworks",
+			} {
+				if !hasPath(fields, path) {
+					t.Errorf("structure lacks %s", path)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildPromptWithoutKnownFields(t *testing.T) {
+	p, err := BuildPrompt("Senior Go Engineer at Acme.", "https://acme.example/jobs/1", nil, "", "ollama")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(p.User, "KNOWN FIELDS") {
+		t.Error("prompt lists known fields without any")
+	}
+	structure := jsonStructure(t, p.System+"\n"+p.User)
+	if !json.Valid([]byte(structure)) {
+		t.Fatalf("structure is not valid JSON:\n%s", structure)
+	}
+}
+
+func TestWithoutKnown(t *testing.T) {
+	structure := `{
+  "a": {
+    "x": "keep",
+    "y": 0
+  },
+  "b": {
+    "z": ["drop"]
+  },
+  "c": false
+}`
+	got := withoutKnown(structure, []knownField{{Path: "a.y"}, {Path: "b.z"}, {Path: "c"}})
+	want := `{
+  "a": {
+    "x": "keep"
+  },
+  "b": {
+  }
+}`
+	if got != want {
+		t.Errorf("withoutKnown =\n%s\nwant\n%s", got, want)
+	}
+	if got := withoutKnown(structure, nil); got != structure {
+		t.Errorf("withoutKnown without known fields changed the structure:\n%s", got)
+	}
+}
+
+func hasPath(fields map[string]any, path string) bool {
+	var v any = fields
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return false
+		}
+		if v, ok = obj[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonStructure returns the JSON structure a prompt asks for.
+func jsonStructure(t *testing.T, prompt string) string {
+	t.Helper()
+	_, rest, ok := strings.Cut(prompt, "Return this JSON structure:\n")
+	if !ok {
+		t.Fatal("prompt has no JSON structure")
+	}
+	structure, _, ok := strings.Cut(rest, "\n}\n")
+	if !ok {
+		t.Fatal("JSON structure is not terminated")
+	}
+	return structure + "\n}"
+}
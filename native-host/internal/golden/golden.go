@@ -0,0 +1,42 @@
+// Package golden compares test output with golden files. Run the tests with
+// -update to rewrite the files, then review the diff before committing.
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// Compare fails t when got differs from the contents of path, or writes got
+// to path when the tests run with -update.
+func Compare(t testing.TB, path string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("result differs from %s\ngot:\n%s", path, got)
+	}
+}
+
+// CompareJSON compares v, as indented JSON, with the contents of path.
+func CompareJSON(t testing.TB, path string, v any) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	Compare(t, path, append(got, '\n'))
+}
@@ -0,0 +1,96 @@
+package grounding
+
+import (
+	"strings"
+	"unicode"
+
+	"native-host/internal/models"
+)
+
+// Span is an evidence quote and where it is in the raw text, in runes.
+// Start and End are -1 when the quote could not be found, which usually
+// means the model paraphrased.
+type Span struct {
+	Field string // key of models.Evidence
+	Quote string
+	Start int
+	End   int
+}
+
+// Found reports whether the quote was located in the text.
+func (s Span) Found() bool { return s.Start >= 0 }
+
+// Spans locates the evidence quotes of job in text. Fields without a quote
+// are left out.
+func Spans(job *models.JobPosting, text string) []Span {
+	ev := job.Evidence
+	var spans []Span
+	for _, q := range []struct{ field, quote string }{
+		{"salary", ev.Salary},
+		{"seniority_level", ev.SeniorityLevel},
+		{"workplace_type", ev.WorkplaceType},
+		{"years_experience", ev.YearsExperience},
+		{"visa_sponsorship", ev.VisaSponsorship},
+	} {
+		quote := strings.TrimSpace(q.quote)
+		if quote == "" {
+			continue
+		}
+		start, end := Locate(text, quote)
+		spans = append(spans, Span{Field: q.field, Quote: quote, Start: start, End: end})
+	}
+	return spans
+}
+
+// Locate returns the rune offsets of the first occurrence of quote in text,
+// ignoring case, differences in whitespace and the quotes, ellipses and
+// trailing punctuation models wrap quotes in. It returns -1, -1 when quote
+// does not occur.
+func Locate(text, quote string) (start, end int) {
+	needle, _ := fold(strings.TrimFunc(quote, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`"'“”‘’«».…`, r)
+	}))
+	if len(needle) == 0 {
+		return -1, -1
+	}
+	hay, pos := fold(text)
+
+	for i := 0; i+len(needle) <= len(hay); i++ {
+		if hay[i] == needle[0] && equalRunes(hay[i:i+len(needle)], needle) {
+			return pos[i], pos[i+len(needle)-1] + 1
+		}
+	}
+	return -1, -1
+}
+
+// fold lower-cases s and collapses each run of whitespace into one space.
+// pos maps each rune of the result to its rune offset in s.
+func fold(s string) (folded []rune, pos []int) {
+	space := false
+	i := 0
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !space && len(folded) > 0 {
+				folded, pos = append(folded, ' '), append(pos, i)
+			}
+			space = true
+		} else {
+			folded, pos = append(folded, unicode.ToLower(r)), append(pos, i)
+			space = false
+		}
+		i++
+	}
+	if space && len(folded) > 0 {
+		folded, pos = folded[:len(folded)-1], pos[:len(pos)-1]
+	}
+	return folded, pos
+}
+
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
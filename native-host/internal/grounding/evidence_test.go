@@ -0,0 +1,79 @@
+package grounding
+
+import (
+	"reflect"
+	"testing"
+
+	"native-host/internal/models"
+)
+
+func TestLocate(t *testing.T) {
+	for _, tc := range []struct {
+		name, text, quote string
+		want              string // the text at the returned offsets, "" when not found
+	}{
+		{"exact", "We are hiring a Senior Go Engineer.", "Senior Go Engineer", "Senior Go Engineer"},
+		{"case", "Work mode: HYBRID (2 days in Berlin)", "hybrid", "HYBRID"},
+		{"multi-byte text", "Gehalt: 80.000 € – Münchner Büro, hybrid möglich.", "münchner büro", "Münchner Büro"},
+		{"astral runes before", "🚀🚀 Remote first, async by default", "Remote first", "Remote first"},
+		{"collapsed whitespace", "You bring 3+   years\n\tof experience with Go.", "3+ years of experience", "3+   years\n\tof experience"},
+		{"whitespace in the quote", "Salary: 80.000 - 95.000 EUR", "80.000  -\n95.000", "80.000 - 95.000"},
+		{"smart quotes", "Visa sponsorship is available for this role.", "“Visa sponsorship is available”", "Visa sponsorship is available"},
+		{"ellipses and trailing period", "We can sponsor visas. Visa sponsorship is available for this role.", "…visa sponsorship is available for this role.", "Visa sponsorship is available for this role"},
+		{"ASCII ellipsis", "Senior level, 5+ years", `"...Senior level..."`, "Senior level"},
+		{"first occurrence", "Remote. Fully remote.", "remote", "Remote"},
+		{"paraphrased", "Salary: 80.000 - 95.000 EUR", "between 80k and 95k", ""},
+		{"only punctuation", "Anything", "“…”", ""},
+		{"longer than the text", "Go", "Go and Rust", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end := Locate(tc.text, tc.quote)
+			if tc.want == "" {
+				if start != -1 || end != -1 {
+					t.Errorf("Locate = %d, %d; want -1, -1", start, end)
+				}
+				return
+			}
+			runes := []rune(tc.text)
+			if start < 0 || end > len(runes) || start >= end {
+				t.Fatalf("Locate = %d, %d; want the offsets of %q", start, end, tc.want)
+			}
+			if got := string(runes[start:end]); got != tc.want {
+				t.Errorf("Locate = %d, %d (%q), want %q", start, end, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFold(t *testing.T) {
+	folded, pos := fold("  Ä\t\n b  ")
+	if string(folded) != "ä b" {
+		t.Errorf("fold = %q, want %q", string(folded), "ä b")
+	}
+	if want := []int{2, 3, 6}; !reflect.DeepEqual(pos, want) {
+		t.Errorf("pos = %v, want %v", pos, want)
+	}
+	if folded, pos := fold(" \n "); len(folded) != 0 || len(pos) != 0 {
+		t.Errorf("fold of whitespace = %q, %v", string(folded), pos)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	job := &models.JobPosting{}
+	job.Evidence.Salary = " 80.000 – 95.000 € "
+	job.Evidence.WorkplaceType = "fully remote"
+	job.Evidence.SeniorityLevel = "  "
+	text := "Senior role. Gehalt 80.000 – 95.000 € brutto. Hybrid."
+
+	want := []Span{
+		{Field: "salary", Quote: "80.000 – 95.000 €", Start: 20, End: 37},
+		{Field: "workplace_type", Quote: "fully remote", Start: -1, End: -1},
+	}
+	got := Spans(job, text)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Spans = %+v, want %+v", got, want)
+	}
+	if !got[0].Found() || got[1].Found() {
+		t.Errorf("Found = %v, %v; want true, false", got[0].Found(), got[1].Found())
+	}
+}
@@ -0,0 +1,361 @@
+// Package grounding checks extracted values against the text they were
+// extracted from. Models invent skills and salaries; a value that can't be
+// found in the posting deserves a second look. The package also locates the
+// quotes a model gives as evidence for its values.
+package grounding
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"native-host/internal/models"
+)
+
+// MinConfidence is the confidence from which a value counts as grounded.
+const MinConfidence = 0.5
+
+// FieldCheck is the outcome for one value. Skills are checked one by one,
+// so a list field has a FieldCheck per element.
+type FieldCheck struct {
+	Field      string // dotted JSON path
+	Value      string
+	Grounded   bool
+	Confidence float64 // 0 to 1
+}
+
+// Check verifies the technical skills, salaries and years of experience of
+// job against text. Values equal to those in known came from the page's
+// structured data rather than the model and are grounded by definition.
+// Unset values are not checked.
+func Check(job *models.JobPosting, text string, known *models.JobPosting) []FieldCheck {
+	lower := strings.ToLower(text)
+	fromPage := known.SetFields()
+
+	var checks []FieldCheck
+	add := func(field, value string, confidence float64) {
+		checks = append(checks, FieldCheck{
+			Field:      field,
+			Value:      value,
+			Grounded:   confidence >= MinConfidence,
+			Confidence: math.Round(confidence*100) / 100,
+		})
+	}
+
+	ts := job.Requirements.TechnicalSkills
+	for _, list := range []struct {
+		field  string
+		skills []string
+	}{
+		{"requirements.technical_skills.programming_languages", ts.ProgrammingLanguages},
+		{"requirements.technical_skills.frameworks", ts.Frameworks},
+		{"requirements.technical_skills.databases", ts.Databases},
+		{"requirements.technical_skills.cloud_platforms", ts.CloudPlatforms},
+		{"requirements.technical_skills.devops_tools", ts.DevOpsTools},
+		{"requirements.technical_skills.other", ts.Other},
+	} {
+		pageSkills, _ := fromPage[list.field].([]string)
+		for _, skill := range list.skills {
+			if strings.TrimSpace(skill) == "" {
+				continue
+			}
+			confidence := skillConfidence(lower, skill)
+			if containsFold(pageSkills, skill) {
+				confidence = 1
+			}
+			add(list.field, skill, confidence)
+		}
+	}
+
+	var amounts []amount
+	for _, n := range []struct {
+		field string
+		value int
+		years bool
+	}{
+		{"compensation.salary_min", job.Compensation.SalaryMin, false},
+		{"compensation.salary_max", job.Compensation.SalaryMax, false},
+		{"requirements.years_experience_min", job.Requirements.YearsExperienceMin, true},
+		{"requirements.years_experience_max", job.Requirements.YearsExperienceMax, true},
+	} {
+		if n.value == 0 {
+			continue
+		}
+		var confidence float64
+		switch {
+		case fromPage[n.field] == n.value:
+			confidence = 1
+		case n.years:
+			confidence = yearsConfidence(lower, n.value)
+		default:
+			if amounts == nil {
+				amounts = parseAmounts(lower)
+			}
+			confidence = salaryConfidence(amounts, n.value)
+		}
+		add(n.field, strconv.Itoa(n.value), confidence)
+	}
+	return checks
+}
+
+// skillAliases are groups of names for the same skill. Finding any name of
+// a skill's group grounds it.
+var skillAliases = [][]string{
+	{"go", "golang"},
+	{"javascript", "js", "ecmascript"},
+	{"typescript", "ts"},
+	{"postgresql", "postgres", "psql"},
+	{"kubernetes", "k8s"},
+	{"aws", "amazon web services"},
+	{"gcp", "google cloud", "google cloud platform"},
+	{"azure", "microsoft azure"},
+	{"c#", "csharp"},
+	{"c++", "cpp"},
+	{".net", "dotnet", ".net core"},
+	{"node.js", "nodejs", "node"},
+	{"react", "reactjs", "react.js"},
+	{"vue", "vue.js", "vuejs"},
+	{"angular", "angularjs"},
+	{"next.js", "nextjs"},
+	{"mongodb", "mongo"},
+	{"elasticsearch", "elastic search", "elastic"},
+	{"sql server", "mssql", "ms sql"},
+	{"ci/cd", "cicd", "continuous integration", "continuous delivery", "continuous deployment"},
+	{"github actions", "gh actions"},
+	{"gitlab ci", "gitlab"},
+	{"kafka", "apache kafka"},
+	{"spark", "apache spark", "pyspark"},
+	{"bigquery", "big query"},
+	{"rabbitmq", "rabbit mq"},
+	{"machine learning", "ml"},
+	{"rest", "restful", "rest api", "rest apis"},
+}
+
+var aliasIndex = func() map[string][]string {
+	idx := map[string][]string{}
+	for _, group := range skillAliases {
+		for _, name := range group {
+			idx[name] = group
+		}
+	}
+	return idx
+}()
+
+// skillConfidence is 1 when the skill is named in the text, 0.9 when an
+// alias is, and lower for names of one or two characters, which also occur
+// as ordinary words ("go", "ts"). A multi-word skill whose words all occur
+// separately gets 0.5.
+func skillConfidence(text, skill string) float64 {
+	name := strings.ToLower(strings.TrimSpace(skill))
+	if containsWord(text, name) {
+		return shortPenalty(name, 1)
+	}
+
+	best := 0.0
+	for _, alias := range aliasIndex[name] {
+		if alias != name && containsWord(text, alias) {
+			best = math.Max(best, shortPenalty(alias, 0.9))
+		}
+	}
+	if best > 0 {
+		return best
+	}
+
+	words := strings.Fields(name)
+	if len(words) < 2 {
+		return 0
+	}
+	for _, w := range words {
+		if !containsWord(text, w) {
+			return 0
+		}
+	}
+	return 0.5
+}
+
+func shortPenalty(name string, confidence float64) float64 {
+	if len([]rune(name)) <= 2 {
+		return confidence * 0.7
+	}
+	return confidence
+}
+
+// containsWord reports whether word occurs in text, not preceded or
+// followed by a letter or digit, nor followed by "+" or "#" (so "c" is not
+// found in "c++").
+func containsWord(text, word string) bool {
+	for i := 0; ; {
+		j := strings.Index(text[i:], word)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(word)
+		if !wordChar(lastRune(text[:start])) && !wordChar(firstRune(text[end:])) &&
+			!strings.HasPrefix(text[end:], "+") && !strings.HasPrefix(text[end:], "#") {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+func wordChar(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+func firstRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+	return ' '
+}
+
+func lastRune(s string) rune {
+	if s == "" {
+		return ' '
+	}
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(strings.TrimSpace(v), strings.TrimSpace(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// amount is a number in the text. scaled is set when a "k" after the end
+// of a range was applied to its start ("80-95k").
+type amount struct {
+	value  float64
+	scaled bool
+}
+
+var (
+	numberRe = regexp.MustCompile(`\d{1,3}(?:[,.\x{00A0}\x{202F} ]\d{3})+(?:[.,]\d{1,2})?|\d+(?:[.,]\d+)?`)
+	rangeKRe = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*(?:-|–|—|to|bis|tot|à|a)\s*\d+(?:[.,]\d+)?\s*(?:k|tsd)\b`)
+	suffixRe = regexp.MustCompile(`^\s*(k|tsd|m|mio)\b`)
+	groupRe  = regexp.MustCompile(`^\d{1,3}([,. ])\d{3}`)
+	spaces   = strings.NewReplacer("\u00a0", " ", "\u202f", " ")
+)
+
+// parseAmounts finds every number in text, with thousands separators of
+// either convention and "k"/"m" suffixes applied.
+func parseAmounts(text string) []amount {
+	var amounts []amount
+	for _, loc := range numberRe.FindAllStringIndex(text, -1) {
+		v, ok := parseNumber(text[loc[0]:loc[1]])
+		if !ok {
+			continue
+		}
+		if m := suffixRe.FindStringSubmatch(text[loc[1]:]); m != nil {
+			if m[1] == "m" || m[1] == "mio" {
+				v *= 1e6
+			} else {
+				v *= 1e3
+			}
+		}
+		amounts = append(amounts, amount{value: v})
+	}
+	for _, m := range rangeKRe.FindAllStringSubmatch(text, -1) {
+		if v, ok := parseNumber(m[1]); ok {
+			amounts = append(amounts, amount{value: v * 1e3, scaled: true})
+		}
+	}
+	return amounts
+}
+
+// parseNumber reads "80,000", "80.000", "80 000", "1.5" and "1,5".
+func parseNumber(s string) (float64, bool) {
+	s = spaces.Replace(s)
+	if groups := groupRe.FindStringSubmatch(s); groups != nil {
+		// The first separator groups thousands; a different last one is
+		// the decimal separator.
+		sep := groups[1]
+		if i := strings.LastIndexAny(s, ",."); i >= 0 && string(s[i]) != sep {
+			s = strings.ReplaceAll(s[:i], sep, "") + "." + s[i+1:]
+		} else {
+			s = strings.ReplaceAll(s, sep, "")
+		}
+	} else {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// salaryConfidence is 1 when the salary appears in the text, 0.8 when it
+// does through a range's shared "k", and 0.6 when it is twelve times a
+// number in the text, i.e. a monthly salary the model annualized.
+func salaryConfidence(amounts []amount, salary int) float64 {
+	best := 0.0
+	for _, a := range amounts {
+		switch {
+		case near(a.value, float64(salary)) && !a.scaled:
+			return 1
+		case near(a.value, float64(salary)):
+			best = math.Max(best, 0.8)
+		case near(a.value*12, float64(salary)):
+			best = math.Max(best, 0.6)
+		}
+	}
+	return best
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 0.005*b
+}
+
+var (
+	yearWordRe  = regexp.MustCompile(`^\s*\+?\s*(?:-|–|to|bis|tot|à)?\s*(?:\d+\s*\+?\s*)?(years?|yrs?|jahre|jahren|jaar|jaren|ans|années|años|anni)\b`)
+	numberWords = map[int][]string{
+		1:  {"one", "ein", "einem", "eins", "één", "un", "une"},
+		2:  {"two", "zwei", "twee", "deux"},
+		3:  {"three", "drei", "drie", "trois"},
+		4:  {"four", "vier", "quatre"},
+		5:  {"five", "fünf", "vijf", "cinq"},
+		6:  {"six", "sechs", "zes"},
+		7:  {"seven", "sieben", "zeven", "sept"},
+		8:  {"eight", "acht", "huit"},
+		9:  {"nine", "neun", "negen", "neuf"},
+		10: {"ten", "zehn", "tien", "dix"},
+	}
+)
+
+// yearsConfidence is 1 when the number, in digits or as a word, is
+// followed by a word for years ("5+ years", "3-5 Jahre", "five years"),
+// and 0.3 when it only occurs somewhere in the text.
+func yearsConfidence(text string, years int) float64 {
+	forms := append([]string{strconv.Itoa(years)}, numberWords[years]...)
+	anywhere := false
+	for _, form := range forms {
+		for i := 0; ; {
+			j := strings.Index(text[i:], form)
+			if j < 0 {
+				break
+			}
+			start, end := i+j, i+j+len(form)
+			i = start + 1
+			if wordChar(lastRune(text[:start])) || wordChar(firstRune(text[end:])) {
+				continue
+			}
+			if yearWordRe.MatchString(text[end:]) {
+				return 1
+			}
+			anywhere = true
+		}
+	}
+	if anywhere {
+		return 0.3
+	}
+	return 0
+}
+
+// String formats a check for logs.
+func (c FieldCheck) String() string {
+	return fmt.Sprintf("%s=%s (%.2f)", c.Field, c.Value, c.Confidence)
+}
@@ -0,0 +1,178 @@
+package grounding
+
+import (
+	"strings"
+	"testing"
+
+	"native-host/internal/models"
+)
+
+func TestParseNumber(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want float64
+	}{
+		{"80000", 80000},
+		{"80.000", 80000},
+		{"80,000", 80000},
+		{"80 000", 80000},
+		{"80 000", 80000},
+		{"80 000", 80000},
+		{"80,000.50", 80000.5},
+		{"80.000,50", 80000.5},
+		{"1.234.567", 1234567},
+		{"1,234,567.89", 1234567.89},
+		{"1.5", 1.5},
+		{"1,5", 1.5},
+		{"42", 42},
+	} {
+		got, ok := parseNumber(tc.in)
+		if !ok || got != tc.want {
+			t.Errorf("parseNumber(%q) = %v, %v; want %v", tc.in, got, ok, tc.want)
+		}
+	}
+}
+
+func TestSalaryConfidence(t *testing.T) {
+	for _, tc := range []struct {
+		text   string
+		salary int
+		want   float64
+	}{
+		{"salary: 80.000 - 95.000 eur", 80000, 1},
+		{"salary: 80,000 to 95,000 usd", 95000, 1},
+		{"80-95k eur", 95000, 1},
+		{"80-95k eur", 80000, 0.8},       // the "k" of the range's end
+		{"80 bis 95 tsd. €", 80000, 0.8}, // German thousands
+		{"€80k-€100k", 100000, 1},
+		{"1.2m budget", 1200000, 1},
+		{"5.000 € brutto im monat", 60000, 0.6}, // annualized monthly salary
+		{"80.400 eur", 80000, 1},                // within 0.5%
+		{"80.500 eur", 80000, 0},
+		{"we have 80 kubernetes clusters", 80000, 0},
+		{"no numbers here", 80000, 0},
+	} {
+		if got := salaryConfidence(parseAmounts(tc.text), tc.salary); got != tc.want {
+			t.Errorf("salaryConfidence(%q, %d) = %v, want %v", tc.text, tc.salary, got, tc.want)
+		}
+	}
+}
+
+func TestYearsConfidence(t *testing.T) {
+	for _, tc := range []struct {
+		text  string
+		years int
+		want  float64
+	}{
+		{"5+ years of experience", 5, 1},
+		{"3-5 jahre berufserfahrung", 3, 1},
+		{"3-5 jahre berufserfahrung", 5, 1},
+		{"3 to 5 years", 3, 1},
+		{"minstens 4 jaar ervaring", 4, 1},
+		{"five years in backend development", 5, 1},
+		{"mindestens zwei jahre erfahrung", 2, 1},
+		{"au moins 3 ans d'expérience", 3, 1},
+		{"a team of 5 engineers", 5, 0.3},
+		{"we have 35 years of history", 3, 0},
+		{"founded in 2015", 5, 0},
+	} {
+		if got := yearsConfidence(tc.text, tc.years); got != tc.want {
+			t.Errorf("yearsConfidence(%q, %d) = %v, want %v", tc.text, tc.years, got, tc.want)
+		}
+	}
+}
+
+func TestSkillConfidence(t *testing.T) {
+	for _, tc := range []struct {
+		text  string
+		skill string
+		want  float64
+	}{
+		{"experience with postgresql and redis", "PostgreSQL", 1},
+		{"experience with postgres", "PostgreSQL", 0.9},
+		{"we deploy on k8s", "Kubernetes", 0.9},
+		{"we write golang", "Go", 0.9}, // the alias is long enough
+		{"golang or go", "Golang", 1},
+		{"you will go far", "Go", 0.7},
+		{"we use c++ and rust", "C", 0},
+		{"we use c++ and rust", "C++", 1},
+		{"django, not go-lang", "Golang", 0.9 * 0.7}, // the short alias "go"
+		{"django developer", "Golang", 0},
+		{"learning about machine vision", "Machine Learning", 0.5},
+		{"ruby on rails", "Python", 0},
+		{"münchen-based, we use typescript", "TypeScript", 1},
+	} {
+		if got := skillConfidence(tc.text, tc.skill); got != tc.want {
+			t.Errorf("skillConfidence(%q, %q) = %v, want %v", tc.text, tc.skill, got, tc.want)
+		}
+	}
+}
+
+func TestContainsWordRuneBoundaries(t *testing.T) {
+	for _, tc := range []struct {
+		text, word string
+		want       bool
+	}{
+		{"go", "go", true},
+		{"ügo", "go", false},
+		{"goü", "go", false},
+		{"über go!", "go", true},
+		{"c#/.net", "c#", true},
+		{"c#", "c", false},
+	} {
+		if got := containsWord(tc.text, tc.word); got != tc.want {
+			t.Errorf("containsWord(%q, %q) = %v, want %v", tc.text, tc.word, got, tc.want)
+		}
+	}
+	if r := lastRune(""); r != ' ' {
+		t.Errorf("lastRune(\"\") = %q", r)
+	}
+	if r := lastRune("grüß"); r != 'ß' {
+		t.Errorf("lastRune(\"grüß\") = %q", r)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	text := `Senior Go Engineer
+We use Go, PostgreSQL and Kubernetes. 3-5 years of experience.
+Salary: 80.000-95.000 EUR`
+
+	job := &models.JobPosting{}
+	job.Requirements.TechnicalSkills.ProgrammingLanguages = []string{"Go", "Rust", " "}
+	job.Requirements.TechnicalSkills.Databases = []string{"PostgreSQL"}
+	job.Requirements.TechnicalSkills.CloudPlatforms = []string{"AWS"}
+	job.Requirements.YearsExperienceMin = 3
+	job.Requirements.YearsExperienceMax = 5
+	job.Compensation.SalaryMin = 80000
+	job.Compensation.SalaryMax = 120000
+
+	// AWS came from the page's structured data.
+	known := &models.JobPosting{}
+	known.Requirements.TechnicalSkills.CloudPlatforms = []string{"aws"}
+
+	ungrounded := map[string]bool{}
+	checks := Check(job, text, known)
+	for _, c := range checks {
+		if !c.Grounded {
+			ungrounded[c.Field+"="+c.Value] = true
+		}
+	}
+	want := []string{
+		"requirements.technical_skills.programming_languages=Rust",
+		"compensation.salary_max=120000",
+	}
+	if len(ungrounded) != len(want) {
+		t.Errorf("ungrounded = %v, want %v", ungrounded, want)
+	}
+	for _, w := range want {
+		if !ungrounded[w] {
+			t.Errorf("%s is not flagged; checks: %v", w, checks)
+		}
+	}
+	if len(checks) != 8 {
+		t.Errorf("%d checks, want 8 (blank skills are skipped): %v", len(checks), checks)
+	}
+	if s := checks[0].String(); !strings.HasPrefix(s, "requirements.technical_skills.programming_languages=Go (") {
+		t.Errorf("String = %q", s)
+	}
+}
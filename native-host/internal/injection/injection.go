@@ -0,0 +1,49 @@
+// Package injection looks for text in a posting that tries to instruct the
+// model extracting it. Postings come from arbitrary web pages, so anything
+// in them may be written for the model rather than for applicants.
+package injection
+
+import (
+	"regexp"
+	"sort"
+)
+
+// patterns are named so that flagged jobs say what was found. They aim at
+// text addressed to a model; ordinary postings, including ones for AI
+// roles, should match none of them.
+var patterns = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"ignore_instructions", regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\s+(all\s+|any\s+)?(of\s+)?(the\s+|your\s+)?(previous|prior|above|earlier|preceding|system|original)\s+(instructions?|prompts?|rules|directions|messages)`)},
+	{"new_instructions", regexp.MustCompile(`(?i)\b(new|updated|real|actual|additional)\s+(system\s+)?instructions\s*:`)},
+	{"role_marker", regexp.MustCompile(`(?im)^\s*(system|assistant)\s*:|<\|?(im_start|im_end|system|endoftext)\|?>|\[/?INST\]|<<\/?SYS>>`)},
+	{"role_change", regexp.MustCompile(`(?i)\byou are (now|no longer)\b|\bpretend (to be|you are)\b|\bfrom now on,? you\b`)},
+	{"addresses_model", regexp.MustCompile(`(?i)\bif you('re| are) an? (ai|llm|language model|bot|chatbot|assistant)\b|\b(note|message|instructions?) (to|for) (the |any )?(ai|llm|assistant|language model|chatbot|gpt)\b`)},
+	{"prompt_leak", regexp.MustCompile(`(?i)\b(reveal|print|repeat|show|output)\s+(your|the)\s+(system\s+)?(prompt|instructions)\b`)},
+	{"schema_keys", regexp.MustCompile(`\b(salary_min|salary_max|seniority_level|workplace_type|job_function|is_remote_friendly|offers_visa_sponsorship|years_experience_min)\b`)},
+	{"hidden_characters", regexp.MustCompile(`[\x{200B}\x{200C}\x{200D}\x{2060}\x{FEFF}]{3,}`)},
+}
+
+// Scan returns the names of the patterns found in any of texts, sorted,
+// or nil when there are none.
+func Scan(texts ...string) []string {
+	found := map[string]bool{}
+	for _, text := range texts {
+		for _, p := range patterns {
+			if !found[p.name] && p.re.MatchString(text) {
+				found[p.name] = true
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
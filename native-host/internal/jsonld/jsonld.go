@@ -0,0 +1,319 @@
+// Package jsonld reads schema.org JobPosting data embedded in career pages
+// as <script type="application/ld+json"> and maps it onto models.JobPosting.
+package jsonld
+
+import (
+	"encoding/json"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"native-host/internal/models"
+)
+
+// Scripts returns the contents of the page's JSON-LD scripts.
+func Scripts(doc *goquery.Document) []string {
+	var blocks []string
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, s *goquery.Selection) {
+		if text := strings.TrimSpace(s.Text()); text != "" {
+			blocks = append(blocks, text)
+		}
+	})
+	return blocks
+}
+
+// FromDocument parses the first JobPosting found in the page's JSON-LD.
+func FromDocument(doc *goquery.Document) *models.JobPosting {
+	return Parse(Scripts(doc))
+}
+
+// Parse maps the first JobPosting found in blocks, or returns nil. Blocks
+// that are not valid JSON are skipped; sites regularly ship broken ones
+// next to a good one.
+func Parse(blocks []string) *models.JobPosting {
+	for _, block := range blocks {
+		var v any
+		if err := json.Unmarshal([]byte(block), &v); err != nil {
+			continue
+		}
+		if node := findJobPosting(v); node != nil {
+			return mapJobPosting(node)
+		}
+	}
+	return nil
+}
+
+// findJobPosting looks through top-level arrays and @graph containers.
+func findJobPosting(v any) map[string]any {
+	switch t := v.(type) {
+	case []any:
+		for _, item := range t {
+			if node := findJobPosting(item); node != nil {
+				return node
+			}
+		}
+	case map[string]any:
+		if hasType(t, "JobPosting") {
+			return t
+		}
+		if graph, ok := t["@graph"]; ok {
+			return findJobPosting(graph)
+		}
+	}
+	return nil
+}
+
+func hasType(node map[string]any, typ string) bool {
+	for _, t := range list(node["@type"]) {
+		if s, ok := t.(string); ok && (s == typ || strings.HasSuffix(s, "/"+typ)) {
+			return true
+		}
+	}
+	return false
+}
+
+func mapJobPosting(node map[string]any) *models.JobPosting {
+	job := &models.JobPosting{}
+
+	job.Metadata.JobTitle = text(node["title"])
+	job.Metadata.DatePosted = date(text(node["datePosted"]))
+	job.Metadata.ValidThrough = date(text(node["validThrough"]))
+
+	job.CompanyInfo.CompanyName = text(node["hiringOrganization"])
+	job.CompanyInfo.Industry = text(node["industry"])
+
+	mapLocation(job, node)
+	job.WorkArrangement.JobType = employmentType(node["employmentType"])
+
+	salary := node["baseSalary"]
+	if salary == nil {
+		salary = node["estimatedSalary"]
+	}
+	mapSalary(job, salary)
+
+	if exp, ok := first(node["experienceRequirements"]).(map[string]any); ok {
+		if months := number(exp["monthsOfExperience"]); months > 0 {
+			job.Requirements.YearsExperienceMin = int(math.Round(months / 12))
+		}
+	}
+	job.Requirements.EducationLevel = educationLevel(node["educationRequirements"])
+
+	return job
+}
+
+func mapLocation(job *models.JobPosting, node map[string]any) {
+	remote := false
+	for _, t := range list(node["jobLocationType"]) {
+		if s, ok := t.(string); ok && strings.EqualFold(s, "TELECOMMUTE") {
+			remote = true
+		}
+	}
+
+	var parts []string
+	for _, loc := range list(node["jobLocation"]) {
+		place, ok := loc.(map[string]any)
+		if !ok {
+			continue
+		}
+		addr, ok := place["address"].(map[string]any)
+		if !ok {
+			// Some sites put a plain string in address.
+			if s := text(place["address"]); s != "" {
+				parts = append(parts, s)
+			}
+			continue
+		}
+		city := text(addr["addressLocality"])
+		region := text(addr["addressRegion"])
+		country := text(addr["addressCountry"])
+		if job.CompanyInfo.LocationCity == "" {
+			job.CompanyInfo.LocationCity = city
+			job.CompanyInfo.LocationCountry = country
+		}
+		parts = append(parts, joinNonEmpty(", ", city, region, country))
+	}
+
+	if remote {
+		job.WorkArrangement.WorkplaceType = "Remote"
+		job.WorkArrangement.IsRemoteFriendly = true
+
+		var allowed []string
+		for _, req := range list(node["applicantLocationRequirements"]) {
+			if s := text(req); s != "" {
+				allowed = append(allowed, s)
+			}
+		}
+		if len(parts) == 0 && len(allowed) > 0 {
+			parts = []string{"Remote (" + strings.Join(allowed, ", ") + ")"}
+			if len(allowed) == 1 {
+				job.CompanyInfo.LocationCountry = allowed[0]
+			}
+		}
+	}
+
+	job.CompanyInfo.LocationFull = strings.Join(dedupe(parts), "; ")
+}
+
+var employmentTypes = map[string]string{
+	"FULL_TIME":  "Full-time",
+	"PART_TIME":  "Part-time",
+	"CONTRACTOR": "Contract",
+	"TEMPORARY":  "Contract",
+	"INTERN":     "Internship",
+}
+
+// employmentType maps the first recognised schema.org value.
+func employmentType(v any) string {
+	for _, t := range list(v) {
+		s, _ := t.(string)
+		key := strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(strings.TrimSpace(s)))
+		if mapped, ok := employmentTypes[key]; ok {
+			return mapped
+		}
+	}
+	return ""
+}
+
+// perYear converts a salary's unitText to a yearly amount.
+var perYear = map[string]float64{
+	"HOUR":  2080,
+	"DAY":   260,
+	"WEEK":  52,
+	"MONTH": 12,
+	"YEAR":  1,
+}
+
+func mapSalary(job *models.JobPosting, v any) {
+	amount, ok := first(v).(map[string]any)
+	if !ok {
+		return
+	}
+
+	var lo, hi float64
+	unit := "YEAR"
+	switch value := amount["value"].(type) {
+	case map[string]any:
+		lo = number(value["minValue"])
+		hi = number(value["maxValue"])
+		if lo == 0 && hi == 0 {
+			lo = number(value["value"])
+		}
+		if u := strings.ToUpper(text(value["unitText"])); u != "" {
+			unit = u
+		}
+	default:
+		lo = number(value)
+	}
+	if u := strings.ToUpper(text(amount["unitText"])); u != "" {
+		unit = u
+	}
+
+	factor, ok := perYear[unit]
+	if !ok || (lo == 0 && hi == 0) {
+		return
+	}
+	job.Compensation.SalaryMin = int(math.Round(lo * factor))
+	job.Compensation.SalaryMax = int(math.Round(hi * factor))
+	job.Compensation.SalaryCurrency = strings.ToUpper(text(amount["currency"]))
+}
+
+// educationLevel maps credentialCategory onto models.EducationLevels.
+func educationLevel(v any) string {
+	var category string
+	switch t := first(v).(type) {
+	case map[string]any:
+		category = text(t["credentialCategory"])
+	default:
+		category = text(t)
+	}
+	category = strings.ToLower(category)
+	switch {
+	case strings.Contains(category, "doctor"), strings.Contains(category, "phd"):
+		return "PhD"
+	case strings.Contains(category, "master") || strings.Contains(category, "postgraduate"):
+		return "Master's"
+	case strings.Contains(category, "bachelor"):
+		return "Bachelor's"
+	}
+	return ""
+}
+
+// text flattens a JSON-LD value to a string: strings and numbers as is,
+// objects by their name, arrays by their first element.
+func text(v any) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(html.UnescapeString(t))
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case map[string]any:
+		if name := text(t["name"]); name != "" {
+			return name
+		}
+		return text(t["@value"])
+	case []any:
+		return text(first(t))
+	}
+	return ""
+}
+
+func number(v any) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case string:
+		f, _ := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(t), ",", ""), 64)
+		return f
+	}
+	return 0
+}
+
+// date keeps the calendar date of an ISO 8601 timestamp.
+func date(s string) string {
+	if len(s) >= 10 && s[4] == '-' && s[7] == '-' {
+		return s[:10]
+	}
+	return s
+}
+
+func list(v any) []any {
+	if items, ok := v.([]any); ok {
+		return items
+	}
+	if v == nil {
+		return nil
+	}
+	return []any{v}
+}
+
+func first(v any) any {
+	if items := list(v); len(items) > 0 {
+		return items[0]
+	}
+	return nil
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
+
+func dedupe(items []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range items {
+		if s != "" && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package jsonld
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"native-host/internal/golden"
+)
+
+// fixtures are pages in testdata, each with the JobPosting read from it in
+// <name>.golden.json.
+var fixtures = []string{
+	"graph_hourly",   // @graph, a broken block first, hourly salary, several locations
+	"remote_monthly", // top-level array, remote, monthly salary as strings
+	"yearly_single",  // plain value salary, string address, IRI @type
+}
+
+func TestFromDocumentGolden(t *testing.T) {
+	for _, name := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			job := FromDocument(loadFixture(t, name))
+			if job == nil {
+				t.Fatal("no JobPosting found")
+			}
+			golden.CompareJSON(t, filepath.Join("testdata", name+".golden.json"), job)
+		})
+	}
+}
+
+func TestSalaryUnits(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		salary   string
+		min, max int
+	}{
+		{"hour", `{"value": {"minValue": 50, "maxValue": 60, "unitText": "HOUR"}}`, 104000, 124800},
+		{"day", `{"value": {"value": 400, "unitText": "DAY"}}`, 104000, 0},
+		{"week", `{"value": {"minValue": 1000}, "unitText": "week"}`, 52000, 0},
+		{"month", `{"value": {"minValue": 5000, "maxValue": 6000}, "unitText": "MONTH"}`, 60000, 72000},
+		{"year default", `{"value": {"minValue": 80000, "maxValue": 95000}}`, 80000, 95000},
+		{"unknown unit", `{"value": {"minValue": 80000}, "unitText": "PROJECT"}`, 0, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			block := `{"@type": "JobPosting", "title": "Engineer", "baseSalary": ` + tc.salary + `}`
+			job := Parse([]string{block})
+			if job == nil {
+				t.Fatal("no JobPosting found")
+			}
+			if c := job.Compensation; c.SalaryMin != tc.min || c.SalaryMax != tc.max {
+				t.Errorf("salary = %d-%d, want %d-%d", c.SalaryMin, c.SalaryMax, tc.min, tc.max)
+			}
+		})
+	}
+}
+
+func TestParseNoJobPosting(t *testing.T) {
+	blocks := []string{
+		`not json`,
+		`{"@context": "https://schema.org", "@type": "Organization", "name": "Acme"}`,
+		`{"@graph": [{"@type": "WebSite"}, {"@type": "BreadcrumbList"}]}`,
+	}
+	if job := Parse(blocks); job != nil {
+		t.Errorf("Parse = %+v, want nil", job)
+	}
+}
+
+func loadFixture(t *testing.T, name string) *goquery.Document {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name+".html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
@@ -0,0 +1,88 @@
+// Package langdetect guesses the language of a posting offline by counting
+// common function words. It only knows the languages we apply in.
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopwords are frequent words of each language. Words shared between
+// languages count for each of them and so don't tip the balance.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "with", "you", "will", "we", "our", "for", "is", "are", "your", "this", "that", "be", "have", "on", "at", "from", "or", "an", "who", "what", "which", "they", "it", "by", "can", "would", "not", "about", "experience", "team", "work", "skills"},
+	"de": {"und", "der", "die", "das", "mit", "für", "wir", "sie", "ist", "ein", "eine", "einen", "im", "zu", "von", "auf", "bei", "dich", "du", "deine", "dein", "unser", "unsere", "oder", "werden", "wird", "sind", "als", "auch", "nicht", "über", "aus", "den", "dem", "des", "erfahrung", "kenntnisse", "aufgaben"},
+	"nl": {"het", "een", "en", "van", "voor", "met", "je", "jij", "wij", "ons", "onze", "zijn", "op", "te", "bij", "naar", "ervaring", "dat", "niet", "ook", "wat", "heb", "hebt", "jouw", "kennis", "werken", "om", "uit", "worden", "wordt", "bent", "hebben", "kun", "binnen"},
+	"fr": {"le", "la", "les", "des", "et", "du", "un", "une", "pour", "avec", "vous", "nous", "notre", "nos", "votre", "est", "sont", "dans", "sur", "au", "aux", "que", "qui", "ce", "cette", "expérience", "poste", "équipe", "par", "pas", "plus", "être", "connaissances"},
+	"es": {"el", "los", "las", "y", "del", "para", "con", "que", "un", "una", "es", "por", "nuestro", "nuestra", "experiencia", "equipo", "buscamos", "tu", "eres", "se", "al", "lo", "como", "más", "conocimientos", "trabajo"},
+	"it": {"il", "gli", "e", "di", "del", "della", "per", "con", "che", "un", "una", "è", "sono", "nostro", "nostra", "esperienza", "siamo", "cerchiamo", "nel", "alla", "dei", "delle", "anche", "non", "lavoro", "conoscenza"},
+}
+
+var names = map[string]string{
+	"en": "English",
+	"de": "German",
+	"nl": "Dutch",
+	"fr": "French",
+	"es": "Spanish",
+	"it": "Italian",
+}
+
+var index = func() map[string][]string {
+	idx := map[string][]string{}
+	for lang, words := range stopwords {
+		for _, w := range words {
+			idx[w] = append(idx[w], lang)
+		}
+	}
+	return idx
+}()
+
+const (
+	maxWords = 2000 // enough to decide; postings repeat themselves
+	minWords = 20   // fewer gives no reliable answer
+	minShare = 0.05 // of the words that must be stopwords of the winner
+	minLead  = 1.3  // how much the winner must beat the runner-up by
+)
+
+// Detect returns the ISO 639-1 code of the language text is written in,
+// or "" when it is too short or too mixed to tell.
+func Detect(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) > maxWords {
+		words = words[:maxWords]
+	}
+	if len(words) < minWords {
+		return ""
+	}
+
+	hits := map[string]int{}
+	for _, w := range words {
+		for _, lang := range index[w] {
+			hits[lang]++
+		}
+	}
+
+	best, second := "", 0
+	for lang, n := range hits {
+		switch {
+		case best == "" || n > hits[best] || (n == hits[best] && lang < best):
+			if best != "" {
+				second = max(second, hits[best])
+			}
+			best = lang
+		case n > second:
+			second = n
+		}
+	}
+	if best == "" || float64(hits[best]) < minShare*float64(len(words)) || float64(hits[best]) < minLead*float64(second) {
+		return ""
+	}
+	return best
+}
+
+// Name is the English name of a language code, or "" for unknown codes.
+func Name(code string) string {
+	return names[code]
+}
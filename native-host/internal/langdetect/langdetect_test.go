@@ -0,0 +1,86 @@
+package langdetect
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	english = `We are looking for a Senior Go Engineer to join our platform team. You will design and build the services that move payments between our customers and their banks, and you will work closely with product and operations.`
+	german  = `Wir suchen eine erfahrene Softwareentwicklerin oder einen erfahrenen Softwareentwickler für unser Team in Berlin. Du entwickelst mit uns die Plattform für den Zahlungsverkehr und bringst Erfahrung mit Go und PostgreSQL mit. Deine Aufgaben sind vielfältig und du arbeitest eng mit dem Produktteam zusammen.`
+	dutch   = `Wij zijn op zoek naar een ervaren backend developer voor ons team in Amsterdam. Je werkt aan het platform voor betalingen en je hebt ervaring met Go en PostgreSQL. Ook ben je niet bang om verantwoordelijkheid te nemen binnen een klein team.`
+	french  = `Nous recherchons un ingénieur backend expérimenté pour rejoindre notre équipe à Paris. Vous serez responsable de la conception des services de paiement et vous travaillerez avec les équipes produit. Une expérience avec Go est un plus pour ce poste.`
+	spanish = `Buscamos un ingeniero backend con experiencia para nuestro equipo en Madrid. Serás responsable del diseño de los servicios de pagos y trabajarás con el equipo de producto. Es importante que tengas conocimientos de Go y de bases de datos.`
+	italian = `Siamo alla ricerca di un ingegnere backend con esperienza per il nostro team di Milano. Sarai responsabile della progettazione dei servizi di pagamento e lavorerai con il team di prodotto. La conoscenza di Go è un requisito per questa posizione.`
+)
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		name, text, want string
+	}{
+		{"English", english, "en"},
+		{"German", german, "de"},
+		{"Dutch", dutch, "nl"},
+		{"French", french, "fr"},
+		{"Spanish", spanish, "es"},
+		{"Italian", italian, "it"},
+		// A German posting with an English tech-stack list is still German.
+		{"German with English terms", german + " Tech stack: Go, Kafka, Kubernetes, Terraform, GitHub Actions, Grafana, Prometheus.", "de"},
+		// As much English as German: neither leads by enough.
+		{"mixed", english + " " + german, ""},
+		{"no stopwords", strings.Repeat("Go Kubernetes Terraform PostgreSQL Kafka ", 10), ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Detect(tc.text); got != tc.want {
+				t.Errorf("Detect = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDetectMinWords(t *testing.T) {
+	words := strings.Fields(strings.Repeat("wir suchen dich für unser team in berlin und ", 3))
+	if got := Detect(strings.Join(words[:minWords-1], " ")); got != "" {
+		t.Errorf("Detect of %d words = %q, want \"\"", minWords-1, got)
+	}
+	if got := Detect(strings.Join(words[:minWords], " ")); got != "de" {
+		t.Errorf("Detect of %d words = %q, want \"de\"", minWords, got)
+	}
+}
+
+func TestDetectMinLead(t *testing.T) {
+	// "the" is only an English stopword and "und" only a German one. The
+	// padding has no stopwords and keeps the text long enough.
+	padding := strings.Repeat("kubernetes ", minWords)
+	for _, tc := range []struct {
+		en, de int
+		want   string
+	}{
+		{10, 10, ""},   // a tie
+		{12, 10, ""},   // 1.2 < minLead
+		{14, 10, "en"}, // 1.4 >= minLead
+		{10, 14, "de"},
+	} {
+		text := padding + strings.Repeat("the ", tc.en) + strings.Repeat("und ", tc.de)
+		if got := Detect(text); got != tc.want {
+			t.Errorf("%d English and %d German stopwords: Detect = %q, want %q", tc.en, tc.de, got, tc.want)
+		}
+	}
+}
+
+func TestDetectMinShare(t *testing.T) {
+	// 2 stopwords in 60 words are under minShare.
+	text := strings.Repeat("kubernetes terraform postgresql ", 19) + "the team works"
+	if got := Detect(text); got != "" {
+		t.Errorf("Detect = %q, want \"\"", got)
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name("nl"); got != "Dutch" {
+		t.Errorf("Name(nl) = %q", got)
+	}
+	if got := Name("xx"); got != "" {
+		t.Errorf("Name(xx) = %q", got)
+	}
+}
@@ -0,0 +1,235 @@
+// Package liveness re-fetches job postings to find out whether they are
+// still open.
+package liveness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+// Status of a posting after a check.
+const (
+	StatusOpen    = "open"
+	StatusClosed  = "closed"
+	StatusUnknown = "unknown" // network error, bot wall, 5xx: try again later
+)
+
+// maxBodyBytes caps how much of a page is scanned for closed markers.
+const maxBodyBytes = 2 << 20
+
+// closedPhrases are lower-case snippets that job boards show on postings
+// that no longer take applications.
+var closedPhrases = []string{
+	"no longer accepting applications",
+	"no longer accepting candidates",
+	"this job is no longer available",
+	"job is no longer available",
+	"this position is no longer available",
+	"this position has been filled",
+	"position has been filled",
+	"this position is no longer open",
+	"this job has expired",
+	"this job posting has expired",
+	"job posting is no longer active",
+	"the job you are looking for is no longer",
+	"this vacancy has been closed",
+	"applications are closed",
+	"nicht mehr verfügbar",
+	"niet meer beschikbaar",
+	"n'est plus disponible",
+}
+
+// careersIndexSegments are final path segments of the listing pages that
+// job boards redirect to once a posting is taken down.
+var careersIndexSegments = map[string]bool{
+	"":               true,
+	"careers":        true,
+	"career":         true,
+	"jobs":           true,
+	"positions":      true,
+	"openings":       true,
+	"vacancies":      true,
+	"join-us":        true,
+	"join":           true,
+	"work-with-us":   true,
+	"open-positions": true,
+}
+
+// Result is the outcome of checking one URL.
+type Result struct {
+	URL        string
+	FinalURL   string
+	StatusCode int
+	Status     string
+	Reason     string
+}
+
+// Checker fetches postings. Client can be replaced, e.g. by one pointing at
+// an httptest server; nil means http.DefaultClient.
+type Checker struct {
+	Client    *http.Client
+	UserAgent string
+}
+
+func (c *Checker) client() *http.Client {
+	if c.Client != nil {
+		return c.Client
+	}
+	return http.DefaultClient
+}
+
+// Check fetches rawURL and classifies the posting. Transport errors are not
+// returned as errors; they yield StatusUnknown so one flaky site doesn't
+// abort a batch.
+func (c *Checker) Check(ctx context.Context, rawURL string) Result {
+	res := Result{URL: rawURL, Status: StatusUnknown}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		res.Reason = fmt.Sprintf("invalid url: %v", err)
+		return res
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := c.client().Do(req)
+	if err != nil {
+		res.Reason = err.Error()
+		return res
+	}
+	defer resp.Body.Close()
+
+	res.StatusCode = resp.StatusCode
+	res.FinalURL = resp.Request.URL.String()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		res.Status = StatusClosed
+		res.Reason = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		return res
+	case resp.StatusCode >= 400:
+		res.Reason = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		return res
+	}
+
+	if redirectedToIndex(req.URL, resp.Request.URL) {
+		res.Status = StatusClosed
+		res.Reason = "redirected to " + res.FinalURL
+		return res
+	}
+
+	text, err := visibleText(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		res.Reason = fmt.Sprintf("read body: %v", err)
+		return res
+	}
+	if phrase := closedPhrase(text); phrase != "" {
+		res.Status = StatusClosed
+		res.Reason = fmt.Sprintf("page says %q", phrase)
+		return res
+	}
+
+	res.Status = StatusOpen
+	return res
+}
+
+// CheckAll checks urls with at most workers requests in flight and returns
+// results in the order of urls.
+func (c *Checker) CheckAll(ctx context.Context, urls []string, workers int) []Result {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]Result, len(urls))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = c.Check(ctx, urls[i])
+			}
+		}()
+	}
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// redirectedToIndex reports whether a redirect landed on a listing page
+// rather than on the posting itself: a shorter path ending in a careers
+// index segment, or Greenhouse's "?error=true" board redirect.
+func redirectedToIndex(original, final *url.URL) bool {
+	if original.String() == final.String() {
+		return false
+	}
+	if final.Query().Get("error") == "true" {
+		return true
+	}
+
+	origPath := strings.Trim(original.Path, "/")
+	finalPath := strings.Trim(final.Path, "/")
+	if origPath == "" || len(finalPath) >= len(origPath) {
+		return false
+	}
+
+	segments := strings.Split(finalPath, "/")
+	return careersIndexSegments[strings.ToLower(segments[len(segments)-1])]
+}
+
+func closedPhrase(text string) string {
+	lower := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	for _, p := range closedPhrases {
+		if strings.Contains(lower, p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// visibleText returns the text content of an HTML document, skipping
+// script and style elements whose strings (e.g. i18n bundles) would cause
+// false positives.
+func visibleText(r io.Reader) (string, error) {
+	z := html.NewTokenizer(r)
+	var b strings.Builder
+	skip := 0
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return b.String(), nil
+			}
+			return b.String(), z.Err()
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			if tag := string(name); tag == "script" || tag == "style" || tag == "noscript" {
+				skip++
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if tag := string(name); (tag == "script" || tag == "style" || tag == "noscript") && skip > 0 {
+				skip--
+			}
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(z.Text())
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
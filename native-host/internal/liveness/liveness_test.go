@@ -0,0 +1,140 @@
+package liveness
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs/open", func(w http.ResponseWriter, r *http.Request) {
+		// Closed phrases inside scripts, e.g. i18n bundles, don't count.
+		fmt.Fprint(w, `<html><head><script>var msg = "This job is no longer available";</script></head>
+<body><h1>Senior Go Engineer</h1><p>Apply now.</p></body></html>`)
+	})
+	mux.HandleFunc("/jobs/filled", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><div class="banner">This job is
+  <b>No Longer Accepting Applications</b></div></body></html>`)
+	})
+	mux.HandleFunc("/jobs/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/jobs/gone", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	mux.HandleFunc("/jobs/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/careers/jobs/1234", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/careers", http.StatusFound)
+	})
+	mux.HandleFunc("/careers", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><h1>Open positions</h1></body></html>`)
+	})
+	mux.HandleFunc("/jobs/old-slug", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/jobs/open", http.StatusMovedPermanently)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCheck(t *testing.T) {
+	srv := newServer(t)
+	c := &Checker{Client: srv.Client()}
+
+	for _, tc := range []struct {
+		path   string
+		status string
+		reason string
+	}{
+		{"/jobs/open", StatusOpen, ""},
+		{"/jobs/old-slug", StatusOpen, ""},
+		{"/jobs/filled", StatusClosed, `page says "no longer accepting applications"`},
+		{"/jobs/missing", StatusClosed, "HTTP 404"},
+		{"/jobs/gone", StatusClosed, "HTTP 410"},
+		{"/jobs/error", StatusUnknown, "HTTP 503"},
+		{"/careers/jobs/1234", StatusClosed, "redirected to " + srv.URL + "/careers"},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			res := c.Check(context.Background(), srv.URL+tc.path)
+			if res.Status != tc.status || res.Reason != tc.reason {
+				t.Errorf("Check = %s (%q), want %s (%q)", res.Status, res.Reason, tc.status, tc.reason)
+			}
+		})
+	}
+}
+
+func TestCheckUnreachable(t *testing.T) {
+	srv := newServer(t)
+	c := &Checker{Client: srv.Client()}
+	target := srv.URL + "/jobs/open"
+	srv.Close()
+
+	if res := c.Check(context.Background(), target); res.Status != StatusUnknown || res.Reason == "" {
+		t.Errorf("Check = %s (%q), want %s with a reason", res.Status, res.Reason, StatusUnknown)
+	}
+}
+
+func TestCheckAllKeepsOrder(t *testing.T) {
+	srv := newServer(t)
+	c := &Checker{Client: srv.Client()}
+
+	paths := []string{"/jobs/missing", "/jobs/open", "/jobs/filled", "/jobs/gone", "/jobs/open"}
+	urls := make([]string, len(paths))
+	for i, p := range paths {
+		urls[i] = srv.URL + p
+	}
+
+	want := []string{StatusClosed, StatusOpen, StatusClosed, StatusClosed, StatusOpen}
+	for i, res := range c.CheckAll(context.Background(), urls, 3) {
+		if res.URL != urls[i] || res.Status != want[i] {
+			t.Errorf("result %d = %s %s, want %s %s", i, res.URL, res.Status, urls[i], want[i])
+		}
+	}
+}
+
+func TestRedirectedToIndex(t *testing.T) {
+	for _, tc := range []struct {
+		from, to string
+		want     bool
+	}{
+		{"https://acme.example/careers/jobs/1234", "https://acme.example/careers", true},
+		{"https://acme.example/careers/jobs/1234", "https://acme.example/", true},
+		{"https://boards.greenhouse.io/acme/jobs/1", "https://boards.greenhouse.io/acme?error=true", true},
+		{"https://acme.example/jobs/1234", "https://acme.example/jobs/1234-senior-go-engineer", false},
+		{"https://acme.example/jobs/1234", "https://acme.example/jobs/1234", false},
+		{"https://acme.example/careers/jobs/1234", "https://acme.example/about", false},
+	} {
+		from, to := mustParse(t, tc.from), mustParse(t, tc.to)
+		if got := redirectedToIndex(from, to); got != tc.want {
+			t.Errorf("redirectedToIndex(%s, %s) = %v, want %v", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestClosedPhrase(t *testing.T) {
+	for _, tc := range []struct{ text, want string }{
+		{"This position\n\t HAS BEEN   filled.", "this position has been filled"},
+		{"Diese Stelle ist leider nicht mehr verfügbar.", "nicht mehr verfügbar"},
+		{"We are accepting applications for this position.", ""},
+	} {
+		if got := closedPhrase(tc.text); got != tc.want {
+			t.Errorf("closedPhrase(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func mustParse(t *testing.T, rawURL string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
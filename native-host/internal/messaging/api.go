@@ -10,5 +10,6 @@ type APIRequest struct {
 type APIResponse struct {
 	OK      bool        `json:"ok"`
 	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"` // machine-readable error kind, e.g. "budget_exceeded"
 	Payload interface{} `json:"payload,omitempty"`
 }
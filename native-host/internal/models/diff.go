@@ -0,0 +1,93 @@
+package models
+
+import "reflect"
+
+// FieldChange is a field whose value differs between two extractions.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// diffIgnored are fields that differ on every run. Evidence quotes are
+// worded differently by every run without the job changing.
+var diffIgnored = map[string]bool{
+	"extracted_at":              true,
+	"evidence.salary":           true,
+	"evidence.seniority_level":  true,
+	"evidence.workplace_type":   true,
+	"evidence.years_experience": true,
+	"evidence.visa_sponsorship": true,
+}
+
+// Diff lists the fields that differ between old and updated, in struct
+// order. Empty and missing slices compare equal.
+func Diff(old, updated *JobPosting) []FieldChange {
+	newFields := map[string]reflect.Value{}
+	walkLeaves(reflect.ValueOf(updated).Elem(), "", func(path string, v reflect.Value) {
+		newFields[path] = v
+	})
+
+	var changes []FieldChange
+	walkLeaves(reflect.ValueOf(old).Elem(), "", func(path string, o reflect.Value) {
+		n := newFields[path]
+		if diffIgnored[path] || (isUnset(o) && isUnset(n)) || reflect.DeepEqual(o.Interface(), n.Interface()) {
+			return
+		}
+		changes = append(changes, FieldChange{Field: path, Old: o.Interface(), New: n.Interface()})
+	})
+	return changes
+}
+
+// FieldValues holds the values several extractions gave one field.
+type FieldValues struct {
+	Field  string `json:"field"`
+	Values []any  `json:"values"`
+}
+
+// diffIgnoredAcrossRuns are also set by the caller rather than the model.
+var diffIgnoredAcrossRuns = map[string]bool{"source_url": true, "prompt_version": true}
+
+// Disagreements lists the fields on which the given extractions don't all
+// agree, in struct order, along with the value each one gave. It also
+// returns the number of fields compared.
+func Disagreements(jobs []*JobPosting) ([]FieldValues, int) {
+	if len(jobs) == 0 {
+		return nil, 0
+	}
+
+	values := map[string][]reflect.Value{}
+	var paths []string
+	for _, j := range jobs {
+		walkLeaves(reflect.ValueOf(j).Elem(), "", func(path string, v reflect.Value) {
+			if diffIgnored[path] || diffIgnoredAcrossRuns[path] {
+				return
+			}
+			if _, ok := values[path]; !ok {
+				paths = append(paths, path)
+			}
+			values[path] = append(values[path], v)
+		})
+	}
+
+	var out []FieldValues
+	for _, path := range paths {
+		vs := values[path]
+		agree := true
+		for _, v := range vs[1:] {
+			if !(isUnset(v) && isUnset(vs[0])) && !reflect.DeepEqual(v.Interface(), vs[0].Interface()) {
+				agree = false
+				break
+			}
+		}
+		if agree {
+			continue
+		}
+		fv := FieldValues{Field: path}
+		for _, v := range vs {
+			fv.Values = append(fv.Values, v.Interface())
+		}
+		out = append(out, fv)
+	}
+	return out, len(paths)
+}
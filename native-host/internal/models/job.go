@@ -3,6 +3,7 @@ package models
 type Message struct {
 	Text     string   `json:"text"`
 	Settings Settings `json:"settings"`
+	JSONLD   []string `json:"jsonLd,omitempty"` // contents of the page's ld+json scripts
 }
 
 type Settings struct {
@@ -11,12 +12,27 @@ type Settings struct {
 	PerplexityKey   string `json:"perplexityKey"`
 	PerplexityModel string `json:"perplexityModel"`
 	SourceURL       string `json:"sourceUrl"` // NEW
+
+	// PromptVersion selects the extraction prompt, e.g. "v1"; empty for
+	// the current default.
+	PromptVersion string `json:"promptVersion,omitempty"`
+
+	// NoCache skips the extraction cache lookup; the fresh result still
+	// replaces the cached one.
+	NoCache bool `json:"noCache,omitempty"`
+
+	// Endpoints, empty for the defaults. Set to point at a remote Ollama
+	// or at a mock server in tests.
+	OllamaURL     string `json:"ollamaUrl,omitempty"`
+	PerplexityURL string `json:"perplexityUrl,omitempty"`
 }
 
 type Response struct {
 	Status   string `json:"status"`
 	Filename string `json:"filename"`
 	JsonFile string `json:"json_file,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Code     string `json:"code,omitempty"` // as in messaging.APIResponse
 }
 
 type JobPosting struct {
@@ -27,15 +43,21 @@ type JobPosting struct {
 	Compensation    Compensation    `json:"compensation"`
 	WorkArrangement WorkArrangement `json:"work_arrangement"`
 	MarketSignals   MarketSignals   `json:"market_signals"`
+	Evidence        Evidence        `json:"evidence"` // asked for from prompt v5 on
 	ExtractedAt     string          `json:"extracted_at"`
 	SourceURL       string          `json:"source_url"`
+	PromptVersion   string          `json:"prompt_version,omitempty"`   // set by the host, not the model
+	PostingLanguage string          `json:"posting_language,omitempty"` // ISO 639-1, detected by the host
+	InjectionFlags  []string        `json:"injection_flags,omitempty"`  // text aimed at the model found in the posting
 }
 
 type JobMetadata struct {
 	JobTitle       string `json:"job_title"`
 	Department     string `json:"department"`
-	SeniorityLevel string `json:"seniority_level"` // Junior, Mid, Senior, Staff, Principal, Lead
-	JobFunction    string `json:"job_function"`    // Backend, Frontend, FullStack, DevOps, Data
+	SeniorityLevel string `json:"seniority_level"`         // Junior, Mid, Senior, Staff, Principal, Lead
+	JobFunction    string `json:"job_function"`            // Backend, Frontend, FullStack, DevOps, Data
+	DatePosted     string `json:"date_posted,omitempty"`   // YYYY-MM-DD, from structured data only
+	ValidThrough   string `json:"valid_through,omitempty"` // YYYY-MM-DD, from structured data only
 }
 
 type CompanyInfo struct {
@@ -99,3 +121,14 @@ type MarketSignals struct {
 	HasTakeHome        bool   `json:"has_take_home"`
 	HasPairProgramming bool   `json:"has_pair_programming"`
 }
+
+// Evidence holds, for the fields the answer to "why?" matters most for, the
+// passage of the posting the model derived each one from, verbatim. Empty
+// when the posting states nothing the value could be traced to.
+type Evidence struct {
+	Salary          string `json:"salary"`
+	SeniorityLevel  string `json:"seniority_level"`
+	WorkplaceType   string `json:"workplace_type"`
+	YearsExperience string `json:"years_experience"`
+	VisaSponsorship string `json:"visa_sponsorship"`
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Merge copies every non-zero field of known over j, so values parsed
+// deterministically from the page win over what the model returned. Slices
+// replace rather than extend. It returns the dotted paths it set.
+func (j *JobPosting) Merge(known *JobPosting) []string {
+	if known == nil {
+		return nil
+	}
+	var paths []string
+	walkSet(reflect.ValueOf(known).Elem(), "", func(path string, src reflect.Value) {
+		fieldByPath(reflect.ValueOf(j).Elem(), path).Set(src)
+		paths = append(paths, path)
+	})
+	sort.Strings(paths)
+	return paths
+}
+
+// Absorb fills the unset fields of j from part and appends the list
+// elements of part that j lacks (ignoring case), for combining extractions
+// of different parts of one posting. Values already in j win.
+func (j *JobPosting) Absorb(part *JobPosting) {
+	if part == nil {
+		return
+	}
+	walkSet(reflect.ValueOf(part).Elem(), "", func(path string, src reflect.Value) {
+		dst := fieldByPath(reflect.ValueOf(j).Elem(), path)
+		switch {
+		case dst.Kind() == reflect.Slice:
+			// appendMissing copies into a new list when dst is empty, so
+			// j never shares part's backing array.
+			dst.Set(reflect.ValueOf(appendMissing(dst.Interface().([]string), src.Interface().([]string))))
+		case isUnset(dst):
+			dst.Set(src)
+		}
+	})
+}
+
+func appendMissing(list, more []string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, s := range list {
+		seen[strings.ToLower(strings.TrimSpace(s))] = true
+	}
+	for _, s := range more {
+		key := strings.ToLower(strings.TrimSpace(s))
+		if key != "" && !seen[key] {
+			seen[key] = true
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// SetFields returns the non-zero leaves of j keyed by dotted JSON path.
+func (j *JobPosting) SetFields() map[string]any {
+	fields := map[string]any{}
+	if j == nil {
+		return fields
+	}
+	walkSet(reflect.ValueOf(j).Elem(), "", func(path string, v reflect.Value) {
+		fields[path] = v.Interface()
+	})
+	return fields
+}
+
+// walkSet calls fn for every non-zero leaf of the struct v.
+func walkSet(v reflect.Value, prefix string, fn func(path string, v reflect.Value)) {
+	walkLeaves(v, prefix, func(path string, f reflect.Value) {
+		if !isUnset(f) {
+			fn(path, f)
+		}
+	})
+}
+
+// walkLeaves calls fn for every non-struct field of the struct v, nested
+// structs included, with its dotted JSON path.
+func walkLeaves(v reflect.Value, prefix string, fn func(path string, v reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		if f := v.Field(i); f.Kind() == reflect.Struct {
+			walkLeaves(f, path, fn)
+		} else {
+			fn(path, f)
+		}
+	}
+}
+
+// isUnset treats empty slices like nil ones.
+func isUnset(v reflect.Value) bool {
+	return v.IsZero() || (v.Kind() == reflect.Slice && v.Len() == 0)
+}
+
+func fieldByPath(v reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if jsonName(t.Field(i)) == name {
+				v = v.Field(i)
+				break
+			}
+		}
+	}
+	return v
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAbsorb(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		into, part func(*JobPosting)
+		want       func(*JobPosting)
+	}{
+		{
+			"fills unset fields",
+			func(j *JobPosting) { j.Metadata.JobTitle = "Go Engineer" },
+			func(j *JobPosting) {
+				j.Compensation.SalaryMin = 80000
+				j.Compensation.HasEquity = true
+			},
+			func(j *JobPosting) {
+				j.Metadata.JobTitle = "Go Engineer"
+				j.Compensation.SalaryMin = 80000
+				j.Compensation.HasEquity = true
+			},
+		},
+		{
+			"earlier values win",
+			func(j *JobPosting) {
+				j.Metadata.JobTitle = "Go Engineer"
+				j.Requirements.YearsExperienceMin = 5
+			},
+			func(j *JobPosting) {
+				j.Metadata.JobTitle = "Senior Go Engineer"
+				j.Requirements.YearsExperienceMin = 3
+			},
+			func(j *JobPosting) {
+				j.Metadata.JobTitle = "Go Engineer"
+				j.Requirements.YearsExperienceMin = 5
+			},
+		},
+		{
+			"appends missing list elements ignoring case",
+			func(j *JobPosting) {
+				j.Requirements.TechnicalSkills.ProgrammingLanguages = []string{"Go", "Python"}
+			},
+			func(j *JobPosting) {
+				j.Requirements.TechnicalSkills.ProgrammingLanguages = []string{"python", " GO ", "Rust", "", "rust"}
+				j.Compensation.Benefits = []string{"Gym"}
+			},
+			func(j *JobPosting) {
+				j.Requirements.TechnicalSkills.ProgrammingLanguages = []string{"Go", "Python", "Rust"}
+				j.Compensation.Benefits = []string{"Gym"}
+			},
+		},
+		{
+			"empty part",
+			func(j *JobPosting) { j.Metadata.JobTitle = "Go Engineer" },
+			func(j *JobPosting) {},
+			func(j *JobPosting) { j.Metadata.JobTitle = "Go Engineer" },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var into, part, want JobPosting
+			tc.into(&into)
+			tc.part(&part)
+			tc.want(&want)
+
+			into.Absorb(&part)
+			if !reflect.DeepEqual(into.SetFields(), want.SetFields()) {
+				t.Errorf("Absorb gave %v, want %v", into.SetFields(), want.SetFields())
+			}
+		})
+	}
+}
+
+func TestAbsorbNil(t *testing.T) {
+	j := JobPosting{}
+	j.Metadata.JobTitle = "Go Engineer"
+	j.Absorb(nil)
+	if j.Metadata.JobTitle != "Go Engineer" || len(j.SetFields()) != 1 {
+		t.Errorf("Absorb(nil) changed the job: %v", j.SetFields())
+	}
+}
+
+func TestAbsorbDoesNotShareLists(t *testing.T) {
+	var into, part JobPosting
+	part.Compensation.Benefits = []string{"Gym"}
+	into.Absorb(&part)
+	into.Compensation.Benefits[0] = "Pension"
+	if part.Compensation.Benefits[0] != "Gym" {
+		t.Error("Absorb copied part's list by reference")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	var j, known JobPosting
+	j.Metadata.JobTitle = "Go Engineer (m/w/d)"
+	j.Compensation.SalaryMin = 70000
+	known.Metadata.JobTitle = "Go Engineer"
+	known.CompanyInfo.CompanyName = "Acme"
+
+	paths := j.Merge(&known)
+	if want := []string{"company_info.company_name", "metadata.job_title"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Merge = %v, want %v", paths, want)
+	}
+	if j.Metadata.JobTitle != "Go Engineer" || j.CompanyInfo.CompanyName != "Acme" || j.Compensation.SalaryMin != 70000 {
+		t.Errorf("Merge gave %v", j.SetFields())
+	}
+	if paths := j.Merge(nil); paths != nil {
+		t.Errorf("Merge(nil) = %v", paths)
+	}
+}
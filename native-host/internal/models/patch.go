@@ -0,0 +1,73 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ApplyPatch merges a partial JobPosting, given as decoded JSON, into j.
+// Objects are merged field by field; arrays and scalars replace the existing
+// value. Unknown keys are rejected so typos don't get silently dropped.
+// It returns the dotted paths of every leaf the patch set.
+func (j *JobPosting) ApplyPatch(patch map[string]any) ([]string, error) {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("marshal patch: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(j); err != nil {
+		return nil, fmt.Errorf("decode patch: %w", err)
+	}
+
+	var paths []string
+	collectPaths("", patch, &paths)
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// ValidatePatch validates j but only reports errors touching one of the
+// given paths, so that a patch is not refused because of an unrelated value
+// the model got wrong earlier.
+func (j *JobPosting) ValidatePatch(paths []string) []FieldError {
+	var errs []FieldError
+	for _, fe := range j.Validate() {
+		for _, p := range paths {
+			if touches(p, fe.Field) {
+				errs = append(errs, fe)
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// touches reports whether a patch setting path can affect an error on field:
+// the same field, one nested inside the other, or one of the _min/_max pair
+// a range error is reported under. A plain prefix match would tie
+// "compensation.salary" to "compensation.salary_currency".
+func touches(path, field string) bool {
+	switch path {
+	case field, field + "_min", field + "_max":
+		return true
+	}
+	return strings.HasPrefix(path, field+".") || strings.HasPrefix(field, path+".")
+}
+
+func collectPaths(prefix string, v map[string]any, out *[]string) {
+	for k, val := range v {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if nested, ok := val.(map[string]any); ok {
+			collectPaths(path, nested, out)
+			continue
+		}
+		*out = append(*out, path)
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidatePatch(t *testing.T) {
+	var j JobPosting
+	j.Compensation.SalaryMin = 90000
+	j.Compensation.SalaryMax = 80000
+	j.Compensation.SalaryCurrency = "EUR"
+	j.Metadata.SeniorityLevel = "Wizard"
+
+	for _, tc := range []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{"salary bound", []string{"compensation.salary_max"}, []string{"compensation.salary"}},
+		{"sibling field", []string{"compensation.salary_currency"}, nil},
+		{"exact field", []string{"metadata.seniority_level"}, []string{"metadata.seniority_level"}},
+		{"whole object", []string{"metadata"}, []string{"metadata.seniority_level"}},
+		{"unrelated", []string{"metadata.job_title"}, nil},
+		{
+			"several",
+			[]string{"compensation.salary_min", "metadata.seniority_level"},
+			[]string{"metadata.seniority_level", "compensation.salary"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, fe := range j.ValidatePatch(tc.paths) {
+				got = append(got, fe.Field)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ValidatePatch(%v) = %v, want %v", tc.paths, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTouches(t *testing.T) {
+	for _, tc := range []struct {
+		path, field string
+		want        bool
+	}{
+		{"compensation.salary_min", "compensation.salary", true},
+		{"compensation.salary_currency", "compensation.salary", false},
+		{"compensation.salary", "compensation.salary_currency", false},
+		{"compensation.salary_min", "compensation.salary_currency", false},
+		{"requirements.years_experience_max", "requirements.years_experience", true},
+		{"requirements", "requirements.years_experience", true},
+	} {
+		if got := touches(tc.path, tc.field); got != tc.want {
+			t.Errorf("touches(%q, %q) = %v, want %v", tc.path, tc.field, got, tc.want)
+		}
+	}
+}
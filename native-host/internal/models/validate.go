@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Allowed values for the enum-like fields of JobPosting. They mirror the
+// choices offered to the model in the extraction prompt. An empty value is
+// always accepted and means "not stated".
+var (
+	SeniorityLevels = []string{"Junior", "Mid", "Senior", "Staff", "Principal", "Lead"}
+	JobFunctions    = []string{"Backend", "Frontend", "FullStack", "DevOps", "Data", "Mobile", "Security", "Embedded"}
+	EducationLevels = []string{"None", "Bachelor's", "Master's", "PhD"}
+	WorkplaceTypes  = []string{"Remote", "Hybrid", "On-site"}
+	JobTypes        = []string{"Full-time", "Part-time", "Contract", "Internship"}
+	UrgencyLevels   = []string{"Standard", "Urgent", "Immediate"}
+)
+
+// FieldError describes an invalid value. Field is the dotted JSON path of the
+// offending field, or of the common prefix for checks spanning two fields
+// (e.g. "compensation.salary" for salary_min > salary_max).
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Validate checks enum fields against the allowed vocabularies and numeric
+// fields for obviously inconsistent values.
+func (j *JobPosting) Validate() []FieldError {
+	var errs []FieldError
+
+	enums := []struct {
+		field   string
+		value   string
+		allowed []string
+	}{
+		{"metadata.seniority_level", j.Metadata.SeniorityLevel, SeniorityLevels},
+		{"metadata.job_function", j.Metadata.JobFunction, JobFunctions},
+		{"requirements.education_level", j.Requirements.EducationLevel, EducationLevels},
+		{"work_arrangement.workplace_type", j.WorkArrangement.WorkplaceType, WorkplaceTypes},
+		{"work_arrangement.job_type", j.WorkArrangement.JobType, JobTypes},
+		{"market_signals.urgency_level", j.MarketSignals.UrgencyLevel, UrgencyLevels},
+	}
+	for _, e := range enums {
+		if e.value != "" && !Contains(e.allowed, e.value) {
+			errs = append(errs, FieldError{
+				Field:   e.field,
+				Message: fmt.Sprintf("%q is not one of %s", e.value, strings.Join(e.allowed, ", ")),
+			})
+		}
+	}
+
+	req := j.Requirements
+	if req.YearsExperienceMin < 0 || req.YearsExperienceMax < 0 {
+		errs = append(errs, FieldError{"requirements.years_experience", "cannot be negative"})
+	} else if req.YearsExperienceMax > 0 && req.YearsExperienceMin > req.YearsExperienceMax {
+		errs = append(errs, FieldError{"requirements.years_experience", "min is greater than max"})
+	}
+
+	comp := j.Compensation
+	if comp.SalaryMin < 0 || comp.SalaryMax < 0 {
+		errs = append(errs, FieldError{"compensation.salary", "cannot be negative"})
+	} else if comp.SalaryMax > 0 && comp.SalaryMin > comp.SalaryMax {
+		errs = append(errs, FieldError{"compensation.salary", "min is greater than max"})
+	}
+
+	if j.MarketSignals.InterviewRounds < 0 {
+		errs = append(errs, FieldError{"market_signals.interview_rounds", "cannot be negative"})
+	}
+
+	return errs
+}
+
+// Contains reports whether value is one of list.
+func Contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package parsers
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"native-host/internal/models"
+)
+
+// ashby handles jobs.ashbyhq.com. The page is rendered client-side from a
+// JSON blob assigned to window.__appData, which is what gets parsed.
+type ashby struct{}
+
+func (ashby) Name() string { return "ashby" }
+
+func (ashby) Match(u *url.URL) bool {
+	return hostIs(u, "ashbyhq.com")
+}
+
+type ashbyAppData struct {
+	Organization struct {
+		Name string `json:"name"`
+	} `json:"organization"`
+	Posting *struct {
+		Title                   string `json:"title"`
+		DepartmentName          string `json:"departmentName"`
+		LocationName            string `json:"locationName"`
+		EmploymentType          string `json:"employmentType"`
+		WorkplaceType           string `json:"workplaceType"`
+		IsRemote                bool   `json:"isRemote"`
+		CompensationTierSummary string `json:"compensationTierSummary"`
+		SalarySummary           string `json:"scrapeableCompensationSalarySummary"`
+	} `json:"posting"`
+}
+
+const ashbyAppDataPrefix = "window.__appData = "
+
+func (ashby) Parse(doc *goquery.Document) (*models.JobPosting, error) {
+	var data ashbyAppData
+	found := false
+	doc.Find("script").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		text := s.Text()
+		i := strings.Index(text, ashbyAppDataPrefix)
+		if i < 0 {
+			return true
+		}
+		// Decode rather than unmarshal: the object is followed by ";" and
+		// possibly more statements.
+		dec := json.NewDecoder(strings.NewReader(text[i+len(ashbyAppDataPrefix):]))
+		found = dec.Decode(&data) == nil
+		return false
+	})
+	if !found || data.Posting == nil || data.Posting.Title == "" {
+		return nil, ErrNotRecognized
+	}
+
+	p := data.Posting
+	job := &models.JobPosting{}
+	job.Metadata.JobTitle = clean(p.Title)
+	job.Metadata.Department = clean(p.DepartmentName)
+	job.CompanyInfo.CompanyName = clean(data.Organization.Name)
+
+	job.WorkArrangement.WorkplaceType = workplaceType(p.WorkplaceType)
+	if job.WorkArrangement.WorkplaceType == "" && p.IsRemote {
+		job.WorkArrangement.WorkplaceType = "Remote"
+	}
+	job.WorkArrangement.IsRemoteFriendly = p.IsRemote || job.WorkArrangement.WorkplaceType == "Remote"
+	job.WorkArrangement.JobType = jobType(p.EmploymentType)
+	setLocation(job, p.LocationName)
+
+	salary := p.SalarySummary
+	if salary == "" {
+		salary = p.CompensationTierSummary
+	}
+	setSalary(job, salary)
+
+	return job, nil
+}
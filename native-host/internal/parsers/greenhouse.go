@@ -0,0 +1,47 @@
+package parsers
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"native-host/internal/models"
+)
+
+// greenhouse handles boards.greenhouse.io (classic layout) and
+// job-boards.greenhouse.io (current layout).
+type greenhouse struct{}
+
+func (greenhouse) Name() string { return "greenhouse" }
+
+func (greenhouse) Match(u *url.URL) bool {
+	return hostIs(u, "greenhouse.io")
+}
+
+func (greenhouse) Parse(doc *goquery.Document) (*models.JobPosting, error) {
+	job := &models.JobPosting{}
+
+	job.Metadata.JobTitle = firstText(doc, "h1.app-title", ".job__title h1", ".job-title")
+	if job.Metadata.JobTitle == "" {
+		return nil, ErrNotRecognized
+	}
+
+	company := strings.TrimPrefix(firstText(doc, ".company-name"), "at ")
+	if company == "" {
+		// <title>Job Application for Senior Engineer at Acme</title>
+		title := clean(doc.Find("title").First().Text())
+		if i := strings.LastIndex(title, " at "); i >= 0 && strings.HasPrefix(title, "Job Application for ") {
+			company = title[i+len(" at "):]
+		}
+	}
+	job.CompanyInfo.CompanyName = company
+
+	setLocation(job, firstText(doc, "#header .location", ".job__location", ".location"))
+
+	if pay := doc.Find(".pay-range").First(); pay.Length() > 0 {
+		setSalary(job, clean(pay.Text()))
+	}
+
+	return job, nil
+}
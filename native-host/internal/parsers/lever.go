@@ -0,0 +1,54 @@
+package parsers
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"native-host/internal/models"
+)
+
+// lever handles jobs.lever.co (and jobs.eu.lever.co) postings.
+type lever struct{}
+
+func (lever) Name() string { return "lever" }
+
+func (lever) Match(u *url.URL) bool {
+	return hostIs(u, "lever.co")
+}
+
+func (lever) Parse(doc *goquery.Document) (*models.JobPosting, error) {
+	job := &models.JobPosting{}
+
+	headline := doc.Find(".posting-headline").First()
+	job.Metadata.JobTitle = clean(headline.Find("h2").First().Text())
+	if job.Metadata.JobTitle == "" {
+		return nil, ErrNotRecognized
+	}
+
+	// The department label reads "Engineering – Platform"; keep the first part.
+	department := clean(headline.Find(".department").First().Text())
+	department, _, _ = strings.Cut(department, " – ")
+	job.Metadata.Department = strings.TrimSuffix(strings.TrimSpace(department), " /")
+
+	// The page title is "Acme - Senior Engineer".
+	title := clean(doc.Find("title").First().Text())
+	if company, _, ok := strings.Cut(title, " - "); ok {
+		job.CompanyInfo.CompanyName = company
+	}
+	if job.CompanyInfo.CompanyName == "" {
+		job.CompanyInfo.CompanyName, _ = doc.Find(".main-header-logo img").Attr("alt")
+	}
+
+	setLocation(job, clean(headline.Find(".location").First().Text()))
+	job.WorkArrangement.JobType = jobType(headline.Find(".commitment").First().Text())
+	if workplace := workplaceType(headline.Find(".workplaceTypes").First().Text()); workplace != "" {
+		job.WorkArrangement.WorkplaceType = workplace
+		job.WorkArrangement.IsRemoteFriendly = workplace == "Remote"
+	}
+
+	setSalary(job, firstText(doc, `[data-qa="salary-range"]`))
+
+	return job, nil
+}
@@ -0,0 +1,204 @@
+// Package parsers reads postings from job boards whose markup is stable
+// enough to parse without a model. Each parser only fills the fields it can
+// read reliably; the rest is left to the LLM and merged afterwards.
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"native-host/internal/models"
+)
+
+// ErrNotRecognized is returned when a page is served from a known host but
+// doesn't have the expected structure, e.g. after a redesign.
+var ErrNotRecognized = errors.New("page layout not recognized")
+
+// Parser produces a partial JobPosting from one job board's pages.
+type Parser interface {
+	Name() string
+	Match(u *url.URL) bool
+	Parse(doc *goquery.Document) (*models.JobPosting, error)
+}
+
+var registry = []Parser{
+	greenhouse{},
+	lever{},
+	ashby{},
+	workable{},
+}
+
+// ForURL returns the parser for rawURL's host, or nil.
+func ForURL(rawURL string) Parser {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	for _, p := range registry {
+		if p.Match(u) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Parse runs the parser matching rawURL. It returns nil and no error when
+// no parser handles the host.
+func Parse(rawURL string, doc *goquery.Document) (*models.JobPosting, error) {
+	p := ForURL(rawURL)
+	if p == nil {
+		return nil, nil
+	}
+	job, err := p.Parse(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Name(), err)
+	}
+	return job, nil
+}
+
+func hostIs(u *url.URL, domains ...string) bool {
+	host := strings.ToLower(u.Hostname())
+	for _, d := range domains {
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstText returns the collapsed text of the first selector that matches
+// a non-empty element.
+func firstText(doc *goquery.Document, selectors ...string) string {
+	for _, sel := range selectors {
+		if text := clean(doc.Find(sel).First().Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// setLocation fills the location fields from a board's location label such
+// as "Berlin, Germany" or "Remote - US".
+func setLocation(job *models.JobPosting, label string) {
+	label = clean(label)
+	if label == "" {
+		return
+	}
+	job.CompanyInfo.LocationFull = label
+
+	if workplace := workplaceType(label); workplace != "" && job.WorkArrangement.WorkplaceType == "" {
+		job.WorkArrangement.WorkplaceType = workplace
+		job.WorkArrangement.IsRemoteFriendly = workplace == "Remote"
+	}
+
+	// Only split the simple single-location case; "Berlin or London" and
+	// lists of offices are left to the model.
+	if strings.ContainsAny(label, ";|/") || strings.Contains(strings.ToLower(label), " or ") {
+		return
+	}
+	parts := strings.Split(label, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	switch {
+	case len(parts) == 2 && workplaceType(parts[0]) != "":
+		job.CompanyInfo.LocationCountry = parts[1] // "Remote, Germany"
+	case len(parts) >= 2 && workplaceType(parts[0]) == "":
+		job.CompanyInfo.LocationCity = parts[0]
+		job.CompanyInfo.LocationCountry = parts[len(parts)-1]
+	}
+}
+
+// workplaceType maps a board's workplace label onto models.WorkplaceTypes.
+func workplaceType(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case strings.Contains(s, "hybrid"):
+		return "Hybrid"
+	case strings.Contains(s, "remote"):
+		return "Remote"
+	case strings.Contains(s, "on-site"), strings.Contains(s, "onsite"),
+		strings.Contains(s, "on site"), strings.Contains(s, "in office"), strings.Contains(s, "in-office"):
+		return "On-site"
+	}
+	return ""
+}
+
+// jobType maps a board's commitment label onto models.JobTypes.
+func jobType(s string) string {
+	s = strings.ToLower(strings.NewReplacer("-", "", " ", "", "_", "").Replace(s))
+	switch {
+	case strings.Contains(s, "fulltime"), strings.Contains(s, "permanent"):
+		return "Full-time"
+	case strings.Contains(s, "parttime"):
+		return "Part-time"
+	case strings.Contains(s, "contract"), strings.Contains(s, "temporary"), strings.Contains(s, "freelance"):
+		return "Contract"
+	case strings.Contains(s, "intern"):
+		return "Internship"
+	}
+	return ""
+}
+
+var (
+	amountRe    = regexp.MustCompile(`(?i)(\d[\d,.]*)\s*(k)?\b`)
+	currencyRe  = regexp.MustCompile(`\b(USD|EUR|GBP|CAD|AUD|CHF|SEK|NOK|DKK|PLN)\b`)
+	currencySym = map[string]string{"$": "USD", "€": "EUR", "£": "GBP"}
+)
+
+// setSalary fills the compensation range from labels such as
+// "$150K – $200K" or "€80,000 - €95,000 EUR". Labels that aren't yearly
+// ranges are ignored.
+func setSalary(job *models.JobPosting, label string) {
+	lower := strings.ToLower(label)
+	if strings.Contains(lower, "hour") || strings.Contains(lower, "/hr") || strings.Contains(lower, "month") {
+		return
+	}
+
+	var amounts []int
+	for _, m := range amountRe.FindAllStringSubmatch(label, -1) {
+		digits := strings.ReplaceAll(m[1], ",", "")
+		if m[2] == "" {
+			// A dot is a thousands separator in "80.000" but not in "1.5k".
+			digits = strings.ReplaceAll(digits, ".", "")
+		}
+		f, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			continue
+		}
+		if m[2] != "" {
+			f *= 1000
+		}
+		if f >= 1000 {
+			amounts = append(amounts, int(f))
+		}
+	}
+	if len(amounts) == 0 {
+		return
+	}
+
+	job.Compensation.SalaryMin = amounts[0]
+	if len(amounts) > 1 {
+		job.Compensation.SalaryMax = amounts[1]
+	}
+	if m := currencyRe.FindString(strings.ToUpper(label)); m != "" {
+		job.Compensation.SalaryCurrency = m
+		return
+	}
+	for sym, code := range currencySym {
+		if strings.Contains(label, sym) {
+			job.Compensation.SalaryCurrency = code
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package parsers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"native-host/internal/golden"
+	"native-host/internal/models"
+)
+
+// Each fixture is a saved posting page in testdata/<name>.html with the
+// expected result in testdata/<name>.golden.json. After changing a parser,
+// run `go test ./internal/parsers -update` and review the golden diff.
+var fixtures = []struct {
+	name   string
+	url    string
+	parser string
+}{
+	{"greenhouse_classic", "https://boards.greenhouse.io/northwind/jobs/4012345", "greenhouse"},
+	{"greenhouse", "https://job-boards.greenhouse.io/contoso/jobs/5098765", "greenhouse"},
+	{"lever", "https://jobs.lever.co/fabrikam/3b1d2c44-8f6e-4a7b-9d21-5e0f4a1b2c3d", "lever"},
+	{"ashby", "https://jobs.ashbyhq.com/tailspin/6f2c1a52-9c1e-4d0e-a1ff-000000000001", "ashby"},
+	{"workable", "https://apply.workable.com/litware/j/4A1B2C3D4E/", "workable"},
+}
+
+func TestParsersGolden(t *testing.T) {
+	for _, fx := range fixtures {
+		t.Run(fx.name, func(t *testing.T) {
+			if p := ForURL(fx.url); p == nil || p.Name() != fx.parser {
+				t.Fatalf("ForURL(%s) = %v, want %s", fx.url, p, fx.parser)
+			}
+
+			job, err := Parse(fx.url, loadFixture(t, fx.name))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			golden.CompareJSON(t, filepath.Join("testdata", fx.name+".golden.json"), job)
+		})
+	}
+}
+
+func TestParseUnknownHost(t *testing.T) {
+	job, err := Parse("https://careers.example.com/jobs/1", loadFixture(t, "greenhouse"))
+	if job != nil || err != nil {
+		t.Errorf("Parse = %v, %v; want nil, nil", job, err)
+	}
+}
+
+func TestParseNotRecognized(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><body><h1>Careers</h1></body></html>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fx := range fixtures {
+		if _, err := Parse(fx.url, doc); !errors.Is(err, ErrNotRecognized) {
+			t.Errorf("%s: err = %v, want ErrNotRecognized", fx.parser, err)
+		}
+	}
+}
+
+func TestSetSalary(t *testing.T) {
+	tests := []struct {
+		label    string
+		min, max int
+		currency string
+	}{
+		{"$150K – $200K", 150000, 200000, "USD"},
+		{"€80.000 - €95.000", 80000, 95000, "EUR"},
+		{"£90K - £120K • Offers Equity", 90000, 120000, "GBP"},
+		{"120,000 - 140,000 CHF", 120000, 140000, "CHF"},
+		{"$65 - $80 per hour", 0, 0, ""},
+		{"Competitive", 0, 0, ""},
+	}
+	for _, tt := range tests {
+		var job models.JobPosting
+		setSalary(&job, tt.label)
+		c := job.Compensation
+		if c.SalaryMin != tt.min || c.SalaryMax != tt.max || c.SalaryCurrency != tt.currency {
+			t.Errorf("setSalary(%q) = %d, %d, %q; want %d, %d, %q",
+				tt.label, c.SalaryMin, c.SalaryMax, c.SalaryCurrency, tt.min, tt.max, tt.currency)
+		}
+	}
+}
+
+func loadFixture(t *testing.T, name string) *goquery.Document {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name+".html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
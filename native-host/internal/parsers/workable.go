@@ -0,0 +1,55 @@
+package parsers
+
+import (
+	"net/url"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"native-host/internal/jsonld"
+	"native-host/internal/models"
+)
+
+// workable handles apply.workable.com and <company>.workable.com. The
+// rendered page marks its fields with data-ui attributes; the server-side
+// HTML only carries the JSON-LD, which is used as the base.
+type workable struct{}
+
+func (workable) Name() string { return "workable" }
+
+func (workable) Match(u *url.URL) bool {
+	return hostIs(u, "workable.com")
+}
+
+func (workable) Parse(doc *goquery.Document) (*models.JobPosting, error) {
+	job := jsonld.FromDocument(doc)
+	if job == nil {
+		job = &models.JobPosting{}
+	}
+
+	if title := firstText(doc, `[data-ui="job-title"]`); title != "" {
+		job.Metadata.JobTitle = title
+	}
+	if job.Metadata.JobTitle == "" {
+		return nil, ErrNotRecognized
+	}
+
+	if company := firstText(doc, `[data-ui="company-name"]`); company != "" {
+		job.CompanyInfo.CompanyName = company
+	}
+	if department := firstText(doc, `[data-ui="job-department"]`); department != "" {
+		job.Metadata.Department = department
+	}
+	if workplace := workplaceType(firstText(doc, `[data-ui="job-workplace"]`)); workplace != "" {
+		job.WorkArrangement.WorkplaceType = workplace
+		job.WorkArrangement.IsRemoteFriendly = workplace == "Remote"
+	}
+	if location := firstText(doc, `[data-ui="job-location"]`); location != "" {
+		setLocation(job, location)
+	}
+	if t := jobType(firstText(doc, `[data-ui="job-type"]`)); t != "" {
+		job.WorkArrangement.JobType = t
+	}
+	setSalary(job, firstText(doc, `[data-ui="job-salary"]`))
+
+	return job, nil
+}
@@ -0,0 +1,298 @@
+// Package preprocess prepares posting text for the model: it strips page
+// chrome, splits the text into sections by their headings and, for
+// postings too long for one prompt, shortens and chunks it.
+package preprocess
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind is what a section is about, guessed from its heading.
+type Kind int
+
+const (
+	KindOther Kind = iota
+	KindAbout
+	KindResponsibilities
+	KindRequirements
+	KindBenefits
+	KindLegal       // equal opportunity statements, privacy notices
+	KindBoilerplate // "similar jobs" lists and the like, never sent
+)
+
+// Section is a heading and the lines up to the next one. The text before
+// the first heading is a section with an empty Heading.
+type Section struct {
+	Heading string
+	Kind    Kind
+	Text    string // including the heading line
+}
+
+// headingKinds are tried in order, so "About the role" is a
+// responsibilities heading rather than an about-the-company one.
+var headingKinds = []struct {
+	kind Kind
+	re   *regexp.Regexp
+}{
+	{KindBoilerplate, regexp.MustCompile(`^((similar|related|recommended|other|more|latest) (jobs|positions|roles|openings|vacancies)|more jobs (at|from)|people (also viewed|who viewed)|jobs you (may|might) (like|be interested in)|explore (more )?jobs)\b`)},
+	{KindLegal, regexp.MustCompile(`^((our )?(commitment to )?(equal (employment )?opportunit|eeo\b|diversity|inclusion)|accommodation|privacy (notice|policy)|data protection|applicant privacy)`)},
+	{KindResponsibilities, regexp.MustCompile(`^((key )?responsibilities|what you('ll| will) (do|be doing)|your (role|mission|tasks|responsibilities|impact)|the (role|job|position|opportunity)|about the (role|job|position|opportunity)|job description|role description|day[- ]to[- ]day|in this role)\b`)},
+	{KindRequirements, regexp.MustCompile(`^((minimum |preferred |basic )?(requirements|qualifications)|what (you('ll)? need|you bring|we('re| are) looking for)|who you are|your (profile|skills|experience|background)|must[- ]haves?|nice[- ]to[- ]haves?|bonus points|(required |technical )?skills|about you)\b`)},
+	{KindBenefits, regexp.MustCompile(`^(benefits|perks|what we offer|we offer|why (join|work with) us|compensation|salary|pay( range)?)\b`)},
+	{KindAbout, regexp.MustCompile(`^(about (us|the (company|team)|[a-z0-9&.' -]{1,30}$)|who we are|our (company|mission|story|team)|company (description|overview))`)},
+}
+
+// boilerplateLines are cookie banners, navigation and footer lines. They
+// are only matched against short lines, so postings that merely mention
+// cookies or a privacy policy keep those sentences.
+var boilerplateLines = regexp.MustCompile(`(?i)` + strings.Join([]string{
+	`(we|this (web)?site|our (web)?site) uses? cookies`,
+	`(accept|reject|allow|decline) (all|necessary|optional)?\s*cookies`,
+	`cookie (settings|preferences|policy|consent|notice)`,
+	`^(accept all|reject all|manage (preferences|cookies)|got it|ok(ay)?)$`,
+	`^(©|\(c\)|copyright\b)`,
+	`all rights reserved`,
+	`^(privacy( policy)?|terms( of (use|service))?|imprint|impressum|sitemap|accessibility|help center|contact us)$`,
+	`^(sign in|log ?in|sign up|join now|register|skip to (main )?content|back to (all )?(jobs|search|results)|share( this job)?|save( job)?|report (this )?job|show more|show less|see more|see less)$`,
+}, "|"))
+
+const maxBoilerplateLine = 160
+
+var (
+	bullet   = regexp.MustCompile(`^([-*•·–]|\d+[.)])\s`)
+	emphasis = regexp.MustCompile(`^[#*_\s]+|[*_\s]+$`)
+)
+
+// Clean removes boilerplate lines and sections from text and collapses
+// runs of blank lines. Everything else is kept verbatim.
+func Clean(text string) string {
+	var out []string
+	for _, s := range Sections(text) {
+		if s.Kind == KindBoilerplate {
+			continue
+		}
+		for _, line := range strings.Split(s.Text, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if len(trimmed) <= maxBoilerplateLine && boilerplateLines.MatchString(trimmed) {
+				continue
+			}
+			if trimmed == "" && len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+				continue
+			}
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// Sections splits text at the lines that look like headings. A boilerplate
+// section also ends at the first line of prose.
+func Sections(text string) []Section {
+	var sections []Section
+	var cur Section
+	var lines []string
+	flush := func() {
+		cur.Text = strings.Join(lines, "\n")
+		if cur.Heading != "" || strings.TrimSpace(cur.Text) != "" {
+			sections = append(sections, cur)
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if heading, kind, ok := parseHeading(line); ok {
+			if len(lines) > 0 || cur.Heading != "" {
+				flush()
+			}
+			cur, lines = Section{Heading: heading, Kind: kind}, nil
+		} else if cur.Kind == KindBoilerplate && isProse(line) {
+			// A "similar jobs" list above the posting ends where the
+			// posting's text starts, even without a heading.
+			flush()
+			cur, lines = Section{}, nil
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return sections
+}
+
+// parseHeading reports whether line is a section heading: a short line
+// that is marked up as one ("## ...", "...:", "**...**", ALL CAPS) or names
+// a known section. List items never are, and neither are the job titles of
+// a "similar jobs" list, so those stay inside their section.
+func parseHeading(line string) (string, Kind, bool) {
+	s := strings.TrimSpace(line)
+	if bullet.MatchString(s) {
+		return "", KindOther, false
+	}
+	marked := strings.HasPrefix(s, "#") || strings.HasPrefix(s, "**") || strings.HasSuffix(s, ":")
+	s = strings.TrimSuffix(emphasis.ReplaceAllString(s, ""), ":")
+	s = emphasis.ReplaceAllString(s, "")
+	if s == "" || utf8.RuneCountInString(s) > 60 || len(strings.Fields(s)) > 8 || strings.HasSuffix(s, ".") {
+		return "", KindOther, false
+	}
+
+	lower := strings.ToLower(s)
+	for _, h := range headingKinds {
+		if h.re.MatchString(lower) {
+			return s, h.kind, true
+		}
+	}
+	if marked || isUpper(s) {
+		return s, KindOther, true
+	}
+	return "", KindOther, false
+}
+
+// isProse reports whether line is a sentence rather than a list entry
+// such as a job title.
+func isProse(line string) bool {
+	s := strings.TrimSpace(line)
+	if bullet.MatchString(s) {
+		return false
+	}
+	words := len(strings.Fields(s))
+	return words >= 10 || (words >= 5 && strings.ContainsAny(s[len(s)-1:], ".!?"))
+}
+
+func isUpper(s string) bool {
+	letters := 0
+	for _, r := range s {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 4
+}
+
+// dropOrder is which sections Shorten gives up first.
+var dropOrder = []Kind{KindLegal, KindAbout, KindOther}
+
+// Shorten fits text into limit runes. It drops whole sections, legal
+// notices first, then the company description, then unrecognized
+// sections, keeping the opening section with the title and everything
+// about the role, its requirements and pay. If that is not enough, the
+// text is cut at the last line that fits.
+func Shorten(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	sections := Sections(text)
+	dropped := make([]bool, len(sections))
+	size := utf8.RuneCountInString(text)
+	for _, kind := range dropOrder {
+		for i := len(sections) - 1; i > 0 && size > limit; i-- {
+			if !dropped[i] && sections[i].Kind == kind {
+				dropped[i] = true
+				size -= utf8.RuneCountInString(sections[i].Text) + 1
+			}
+		}
+	}
+
+	var kept []string
+	for i, s := range sections {
+		if !dropped[i] {
+			kept = append(kept, s.Text)
+		}
+	}
+	return cutLines(strings.Join(kept, "\n"), limit)
+}
+
+// Chunk splits text into pieces of at most limit runes at section
+// boundaries, or at line boundaries within sections that are too long on
+// their own. Every chunk after the first starts with the opening lines of
+// the posting, which usually name the job and company, so that each can be
+// extracted on its own.
+func Chunk(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	sections := Sections(text)
+	lead := cutLines(sections[0].Text, limit/4)
+	room := limit - utf8.RuneCountInString(lead) - 1
+
+	// Only the first piece goes into the first chunk, which has no lead
+	// and so may be up to limit long.
+	pieces := splitLines(sections[0].Text, limit)
+	if len(pieces) > 1 {
+		pieces = append(pieces[:1], splitLines(strings.Join(pieces[1:], "\n"), room)...)
+	}
+	for _, s := range sections[1:] {
+		pieces = append(pieces, splitLines(s.Text, room)...)
+	}
+
+	var chunks []string
+	var cur strings.Builder
+	for _, p := range pieces {
+		max := room
+		if len(chunks) == 0 {
+			max = limit
+		}
+		if cur.Len() > 0 && utf8.RuneCountInString(cur.String())+1+utf8.RuneCountInString(p) > max {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+		}
+		if cur.Len() == 0 && len(chunks) > 0 {
+			cur.WriteString(lead)
+		}
+		if cur.Len() > 0 {
+			cur.WriteByte('\n')
+		}
+		cur.WriteString(p)
+	}
+	if cur.Len() > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+// splitLines splits text into pieces of at most limit runes at line
+// boundaries. Single lines longer than limit are cut.
+func splitLines(text string, limit int) []string {
+	var pieces []string
+	var cur []string
+	size := 0
+	for _, line := range strings.Split(text, "\n") {
+		if len(cur) > 0 && utf8.RuneCountInString(line) > limit {
+			pieces = append(pieces, strings.Join(cur, "\n"))
+			cur, size = nil, 0
+		}
+		for utf8.RuneCountInString(line) > limit {
+			r := []rune(line)
+			pieces = append(pieces, string(r[:limit]))
+			line = string(r[limit:])
+		}
+		n := utf8.RuneCountInString(line)
+		if len(cur) > 0 && size+1+n > limit {
+			pieces = append(pieces, strings.Join(cur, "\n"))
+			cur, size = nil, 0
+		}
+		if len(cur) > 0 {
+			size++
+		}
+		cur = append(cur, line)
+		size += n
+	}
+	if len(cur) > 0 {
+		pieces = append(pieces, strings.Join(cur, "\n"))
+	}
+	return pieces
+}
+
+// cutLines returns the longest prefix of text that ends at a line boundary
+// and has at most limit runes, or the first limit runes when even the
+// first line is longer.
+func cutLines(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return splitLines(text, limit)[0]
+}
@@ -0,0 +1,209 @@
+package preprocess
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const posting = `Senior Go Engineer
+Acme GmbH · Berlin · Hybrid
+
+About the role
+You will build the payment platform and own its services end to end.
+
+Requirements:
+- 5+ years of backend experience
+- Go, PostgreSQL, Kubernetes
+
+What we offer
+- 80.000–95.000 EUR
+- 30 days of vacation
+
+About Acme
+Acme builds payment software for small shops across Europe.
+
+Equal Opportunity Employer
+Acme is an equal opportunity employer and values diversity.`
+
+func TestSections(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		text string
+		want []Kind
+	}{
+		{
+			"posting",
+			posting,
+			[]Kind{KindOther, KindResponsibilities, KindRequirements, KindBenefits, KindAbout, KindLegal},
+		},
+		{
+			"markdown and caps headings",
+			"# Platform Engineer\nIntro line.\n## RESPONSIBILITIES\n- Run things\n**Who you are**\nSomeone.\nBENEFITS PACKAGE\nGood ones.",
+			[]Kind{KindOther, KindResponsibilities, KindRequirements, KindBenefits},
+		},
+		{
+			// "diversity" in a heading that is about something else is
+			// no longer a legal section.
+			"legal anchored",
+			"Engineer\n\nOur commitment to diversity:\nWe mean it.\n\nTeam diversity and growth:\nWe hire across Europe.",
+			[]Kind{KindOther, KindLegal, KindOther},
+		},
+		{
+			"similar jobs before the description",
+			"Similar jobs\nBackend Engineer\nStaff Engineer, Payments\n\nWe are looking for a Senior Go Engineer to build our payment platform.\nYou will own services end to end.",
+			[]Kind{KindBoilerplate, KindOther},
+		},
+		{
+			"list items are not headings",
+			"Requirements:\n- Benefits:\n1. Privacy policy:",
+			[]Kind{KindRequirements},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []Kind
+			for _, s := range Sections(tc.text) {
+				got = append(got, s.Kind)
+			}
+			if !equalKinds(got, tc.want) {
+				t.Errorf("kinds = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSectionsKeepText(t *testing.T) {
+	var texts []string
+	for _, s := range Sections(posting) {
+		texts = append(texts, s.Text)
+	}
+	if got := strings.Join(texts, "\n"); got != posting {
+		t.Errorf("joined sections differ from the text:\n%s", got)
+	}
+}
+
+func TestClean(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			"chrome lines",
+			"Skip to main content\nSign in\nSenior Go Engineer\n\n\n\nWe use cookies to improve your experience.\nAccept all cookies\nBuild things.\nShare this job\n© 2026 Acme GmbH",
+			"Senior Go Engineer\n\nBuild things.",
+		},
+		{
+			"prose mentioning cookies is kept",
+			"Engineer\nYou will work on the consent service that decides which cookie settings a visitor sees across all our shops, including the cookie policy pages and their translations.",
+			"Engineer\nYou will work on the consent service that decides which cookie settings a visitor sees across all our shops, including the cookie policy pages and their translations.",
+		},
+		{
+			"similar jobs after the description",
+			"Go Engineer\nBuild the platform.\n\nSimilar jobs\nBackend Engineer\nStaff Engineer",
+			"Go Engineer\nBuild the platform.\n",
+		},
+		{
+			"similar jobs before the description",
+			"Similar jobs\nBackend Engineer\nStaff Engineer, Payments\n\nWe are looking for a Senior Go Engineer to build our payment platform.\nRequirements:\n- Go",
+			"We are looking for a Senior Go Engineer to build our payment platform.\nRequirements:\n- Go",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Clean(tc.text); got != tc.want {
+				t.Errorf("Clean =\n%q\nwant\n%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestShorten(t *testing.T) {
+	size := utf8.RuneCountInString(posting)
+	legal := "Equal Opportunity Employer\nAcme is an equal opportunity employer and values diversity."
+	about := "About Acme\nAcme builds payment software for small shops across Europe."
+
+	for _, tc := range []struct {
+		name    string
+		limit   int
+		without []string
+		with    []string
+	}{
+		{"fits", size, nil, []string{legal, about}},
+		{"drops legal first", size - 10, []string{legal}, []string{about, "Requirements:"}},
+		{"then about", size - utf8.RuneCountInString(legal) - 10, []string{legal, about}, []string{"Requirements:", "What we offer"}},
+		{"cuts at a line", 60, []string{"Requirements:"}, []string{"Senior Go Engineer"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Shorten(posting, tc.limit)
+			if n := utf8.RuneCountInString(got); n > tc.limit {
+				t.Errorf("Shorten returned %d runes, limit %d", n, tc.limit)
+			}
+			for _, s := range tc.without {
+				if strings.Contains(got, s) {
+					t.Errorf("Shorten kept %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tc.with {
+				if !strings.Contains(got, s) {
+					t.Errorf("Shorten dropped %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestChunk(t *testing.T) {
+	for _, tc := range []struct {
+		limit int
+		whole bool // no line is longer than a chunk, so none is cut
+	}{
+		{len(posting), true},
+		{200, true},
+		{120, true},
+		{80, false},
+	} {
+		chunks := Chunk(posting, tc.limit)
+		if tc.limit >= len(posting) && len(chunks) != 1 {
+			t.Errorf("limit %d: %d chunks, want 1", tc.limit, len(chunks))
+		}
+
+		lead := cutLines(Sections(posting)[0].Text, tc.limit/4)
+		var rest []string
+		for i, c := range chunks {
+			if n := utf8.RuneCountInString(c); n > tc.limit {
+				t.Errorf("limit %d: chunk %d has %d runes", tc.limit, i, n)
+			}
+			if i > 0 {
+				var ok bool
+				if c, ok = strings.CutPrefix(c, lead+"\n"); !ok {
+					t.Errorf("limit %d: chunk %d does not start with the lead %q:\n%s", tc.limit, i, lead, c)
+				}
+			}
+			rest = append(rest, c)
+		}
+		if got := strings.Join(rest, "\n"); tc.whole && got != posting {
+			t.Errorf("limit %d: chunks without their lead differ from the posting:\n%s", tc.limit, got)
+		}
+	}
+}
+
+func TestChunkLongLine(t *testing.T) {
+	text := "Title\n" + strings.Repeat("ü", 250)
+	for i, c := range Chunk(text, 100) {
+		if n := utf8.RuneCountInString(c); n > 100 || !utf8.ValidString(c) {
+			t.Errorf("chunk %d: %d runes, valid UTF-8 %v", i, n, utf8.ValidString(c))
+		}
+	}
+}
+
+func equalKinds(a, b []Kind) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+// Package redact replaces personal data in posting text with placeholders
+// before it is sent to a remote model. Page text captured while logged in
+// can include the user's own name, email or profile links next to the
+// posting.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Map maps each placeholder to the text it replaced.
+type Map map[string]string
+
+var (
+	emailRe   = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	profileRe = regexp.MustCompile(`(?i)(https?://)?([a-z]{2,3}\.)?linkedin\.com/in/[^\s/?#]+/?`)
+	phoneRe   = regexp.MustCompile(`(\+\d{1,3}[\s.-]?)?(\(\d{1,4}\)[\s.-]?)?\d{2,4}([\s.-]\d{2,4}){1,4}`)
+)
+
+// Phone numbers have 9 to 15 digits; fewer are years, dates and amounts.
+const minPhoneDigits, maxPhoneDigits = 9, 15
+
+const currencyWindow = 12
+
+var (
+	phoneSepRe = regexp.MustCompile(`[\s.()-]+`)
+	// A currency sign or code next to a number, or the "k" of "80k",
+	// makes it an amount. They are matched within currencyWindow bytes.
+	currencyAfterRe  = regexp.MustCompile(`^\s*([$€£¥₹]|(?i:eur|usd|gbp|chf|sek|nok|dkk|pln|czk|cad|aud|jpy|inr|k|tsd)\b)`)
+	currencyBeforeRe = regexp.MustCompile(`([$€£¥₹]|\b(?i:eur|usd|gbp|chf|sek|nok|dkk|pln|czk|cad|aud|jpy|inr))\s*$`)
+)
+
+// Text replaces email addresses, phone numbers, LinkedIn profile URLs and
+// every occurrence of terms (case-insensitive) with placeholders such as
+// [REDACTED_EMAIL_1]. Equal values share a placeholder.
+func Text(text string, terms []string) (string, Map) {
+	r := New(terms)
+	text = r.Text(text)
+	return text, r.Map()
+}
+
+// Redactor redacts several texts with one set of placeholders, so that a
+// value in both the posting and its structured data is replaced the same
+// way in each.
+type Redactor struct {
+	terms []*regexp.Regexp
+	r     *redactor
+}
+
+// New returns a Redactor for the personal data Text looks for.
+func New(terms []string) *Redactor {
+	// Longest first, so "Jane Doe" wins over "Jane".
+	terms = append([]string(nil), terms...)
+	sort.Slice(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+
+	rd := &Redactor{r: &redactor{m: Map{}, seen: map[string]string{}, counts: map[string]int{}}}
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term == "" {
+			continue
+		}
+		rd.terms = append(rd.terms, regexp.MustCompile(`(?i)`+boundary(term, true)+regexp.QuoteMeta(term)+boundary(term, false)))
+	}
+	return rd
+}
+
+// Text redacts text like the package-level Text.
+func (rd *Redactor) Text(text string) string {
+	for _, re := range rd.terms {
+		text = rd.r.replace(text, re, "NAME", nil)
+	}
+	text = rd.r.replace(text, profileRe, "PROFILE", nil)
+	text = rd.r.replace(text, emailRe, "EMAIL", nil)
+	return rd.r.replace(text, phoneRe, "PHONE", isPhone)
+}
+
+// Map returns the placeholders used so far.
+func (rd *Redactor) Map() Map {
+	return rd.r.m
+}
+
+// isPhone reports whether text[start:end], a match of phoneRe, is a phone
+// number rather than an amount ("80.000-100.000 EUR", "120 000 SEK") or an
+// ID ("2024-10-1234-56"). Without a leading "+" or "(" a number must be
+// grouped like one: a national number starting with 0, or 3-3-4 digits as
+// in North America; and groups of three digits throughout are thousands.
+func isPhone(text string, start, end int) bool {
+	s := text[start:end]
+	digits := 0
+	for _, c := range s {
+		if unicode.IsDigit(c) {
+			digits++
+		}
+	}
+	if digits < minPhoneDigits || digits > maxPhoneDigits {
+		return false
+	}
+	before, after := text[max(0, start-currencyWindow):start], text[end:min(len(text), end+currencyWindow)]
+	if currencyBeforeRe.MatchString(before) || currencyAfterRe.MatchString(after) {
+		return false
+	}
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "(") {
+		return true
+	}
+
+	groups := phoneSepRe.Split(s, -1)
+	thousands := true
+	for _, g := range groups[1:] {
+		thousands = thousands && len(g) == 3
+	}
+	if thousands {
+		return false
+	}
+	northAmerican := len(groups) == 3 && len(groups[0]) == 3 && len(groups[1]) == 3 && len(groups[2]) == 4
+	return strings.HasPrefix(s, "0") || northAmerican
+}
+
+// Restore puts the original text back in place of the placeholders in s.
+func (m Map) Restore(s string) string {
+	for placeholder, orig := range m {
+		s = strings.ReplaceAll(s, placeholder, orig)
+	}
+	return s
+}
+
+// String lists the replacements for the local log.
+func (m Map) String() string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%q", k, m[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+type redactor struct {
+	m      Map
+	seen   map[string]string // lower-cased original -> placeholder
+	counts map[string]int
+}
+
+// replace replaces the matches of re in text. keep, when not nil, is
+// given each match with its surroundings and decides whether it is
+// replaced.
+func (r *redactor) replace(text string, re *regexp.Regexp, kind string, keep func(text string, start, end int) bool) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		if keep != nil && !keep(text, loc[0], loc[1]) {
+			continue
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(r.placeholder(kind, text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func (r *redactor) placeholder(kind, s string) string {
+	key := strings.ToLower(s)
+	if p, ok := r.seen[key]; ok {
+		return p
+	}
+	r.counts[kind]++
+	p := fmt.Sprintf("[REDACTED_%s_%d]", kind, r.counts[kind])
+	r.seen[key], r.m[p] = p, s
+	return p
+}
+
+// boundary requires a word boundary next to a term that starts or ends
+// with a word character, so "Ann" doesn't match inside "Annual". Go's \b
+// only knows ASCII word characters, so other terms go without.
+func boundary(term string, start bool) string {
+	c, _ := utf8.DecodeLastRuneInString(term)
+	if start {
+		c, _ = utf8.DecodeRuneInString(term)
+	}
+	if c < utf8.RuneSelf && (c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)) {
+		return `\b`
+	}
+	return ""
+}
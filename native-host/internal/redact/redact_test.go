@@ -0,0 +1,111 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextKeepsAmountsAndIDs(t *testing.T) {
+	for _, text := range []string{
+		"Gehalt: 80.000-100.000 EUR",
+		"Lön: 120 000-150 000 SEK per år",
+		"Salary: $120,000 - $150,000",
+		"Salary: EUR 80.000 - 95.000",
+		"Compensation 800 000-1 000 000 NOK",
+		"Budget: 85.000-95.000k",
+		"Req ID: 2024-10-1234-56",
+		"Posted 2024-10-15, closes 2024-11-30",
+		"Ref. 12-3456-7890-12",
+		"Team of 120 engineers in 14 countries since 2012",
+	} {
+		if got, m := Text(text, nil); got != text {
+			t.Errorf("Text(%q) = %q (%s), want it unchanged", text, got, m)
+		}
+	}
+}
+
+func TestTextPhones(t *testing.T) {
+	for _, tc := range []struct {
+		text, phone string
+	}{
+		{"Call +49 30 1234 5678 today", "+49 30 1234 5678"},
+		{"Tel.: +31 612 345 678", "+31 612 345 678"},
+		{"Phone (030) 1234-5678", "(030) 1234-5678"},
+		{"Ring 030 1234 5678", "030 1234 5678"},
+		{"Mobile: 06 12 34 56 78", "06 12 34 56 78"},
+		{"Call 415-555-0123 for details", "415-555-0123"},
+		{"Or +44 20 7946 0958.", "+44 20 7946 0958"},
+	} {
+		got, m := Text(tc.text, nil)
+		want := strings.Replace(tc.text, tc.phone, "[REDACTED_PHONE_1]", 1)
+		if got != want {
+			t.Errorf("Text(%q) = %q, want %q", tc.text, got, want)
+		}
+		if m["[REDACTED_PHONE_1]"] != tc.phone {
+			t.Errorf("Text(%q) maps %v, want the phone %q", tc.text, m, tc.phone)
+		}
+	}
+}
+
+func TestTextEmailsAndProfiles(t *testing.T) {
+	text := "Contact jane.doe+jobs@example.co.uk or JANE.DOE+JOBS@EXAMPLE.CO.UK, see https://www.linkedin.com/in/jane-doe-123/ and hr@acme.example."
+	got, m := Text(text, nil)
+	want := "Contact [REDACTED_EMAIL_1] or [REDACTED_EMAIL_1], see [REDACTED_PROFILE_1] and [REDACTED_EMAIL_2]."
+	if got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+	if restored := m.Restore(got); !strings.EqualFold(restored, text) {
+		t.Errorf("Restore = %q, want %q", restored, text)
+	}
+}
+
+func TestTextTerms(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		terms []string
+		text  string
+		want  string
+	}{
+		{
+			"whole words only",
+			[]string{"Ann"},
+			"Ann applied. Annual bonus, Joanna and ann.",
+			"[REDACTED_NAME_1] applied. Annual bonus, Joanna and [REDACTED_NAME_1].",
+		},
+		{
+			"longest first",
+			[]string{"Jane", "Jane Doe"},
+			"Jane Doe, or Jane for short",
+			"[REDACTED_NAME_1], or [REDACTED_NAME_2] for short",
+		},
+		{
+			"non-ASCII edges",
+			[]string{"Zoë", "Øystein"},
+			"Zoë and Øystein Berg",
+			"[REDACTED_NAME_2] and [REDACTED_NAME_1] Berg", // longest first
+		},
+		{
+			"regexp characters",
+			[]string{"C++ (Jane)", "  "},
+			"Jane likes C++ (Jane) a lot",
+			"Jane likes [REDACTED_NAME_1] a lot",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, _ := Text(tc.text, tc.terms); got != tc.want {
+				t.Errorf("Text = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	text := "Jane Doe, jane@example.com, +49 30 1234 5678"
+	redacted, m := Text(text, []string{"Jane Doe"})
+	if strings.Contains(redacted, "Jane") || strings.Contains(redacted, "1234") {
+		t.Fatalf("Text left personal data: %q", redacted)
+	}
+	if got := m.Restore(redacted); got != text {
+		t.Errorf("Restore = %q, want %q", got, text)
+	}
+}
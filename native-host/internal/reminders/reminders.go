@@ -0,0 +1,120 @@
+// Package reminders evaluates follow-up rules against the job pipeline.
+package reminders
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+)
+
+// Reminder is a rule that is currently due for a job.
+type Reminder struct {
+	JobID       int64
+	JobTitle    string
+	CompanyName string
+	Status      string
+	Rule        string
+	Message     string
+	Since       time.Time // the timestamp the rule counted from
+	DueAt       time.Time // Since + rule days
+	DaysWaiting int
+}
+
+// ValidateRules rejects rules that could never fire or reference an
+// unknown anchor.
+func ValidateRules(rules []config.ReminderRule) error {
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		if r.Name == "" {
+			return fmt.Errorf("reminder rule without a name")
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("duplicate reminder rule %q", r.Name)
+		}
+		seen[r.Name] = true
+
+		if r.Status == "" {
+			return fmt.Errorf("reminder rule %q: status is required", r.Name)
+		}
+		if r.Days <= 0 {
+			return fmt.Errorf("reminder rule %q: days must be positive", r.Name)
+		}
+		known := false
+		for _, a := range config.ReminderAnchors {
+			if a == r.Since {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("reminder rule %q: since must be one of %s", r.Name, strings.Join(config.ReminderAnchors, ", "))
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the reminders due at now, oldest first. Snoozed
+// reminders are skipped until the snooze runs out; dismissed ones until the
+// rule's anchor timestamp moves past the dismissal.
+func Evaluate(rules []config.ReminderRule, jobs []db.ReminderCandidate, states map[db.ReminderKey]db.ReminderState, now time.Time) []Reminder {
+	var due []Reminder
+	for _, job := range jobs {
+		for _, rule := range rules {
+			if job.Status != rule.Status {
+				continue
+			}
+
+			since := anchor(rule.Since, job)
+			if since.IsZero() {
+				continue
+			}
+			dueAt := since.AddDate(0, 0, rule.Days)
+			if now.Before(dueAt) {
+				continue
+			}
+
+			state := states[db.ReminderKey{JobID: job.JobID, Rule: rule.Name}]
+			if now.Before(state.SnoozedUntil) {
+				continue
+			}
+			if !state.DismissedAt.IsZero() && !since.After(state.DismissedAt) {
+				continue
+			}
+
+			due = append(due, Reminder{
+				JobID:       job.JobID,
+				JobTitle:    job.JobTitle,
+				CompanyName: job.CompanyName,
+				Status:      job.Status,
+				Rule:        rule.Name,
+				Message:     rule.Message,
+				Since:       since,
+				DueAt:       dueAt,
+				DaysWaiting: int(now.Sub(since).Hours() / 24),
+			})
+		}
+	}
+
+	sort.SliceStable(due, func(a, b int) bool {
+		return due[a].Since.Before(due[b].Since)
+	})
+	return due
+}
+
+func anchor(since string, job db.ReminderCandidate) time.Time {
+	switch since {
+	case "applied_date":
+		if !job.AppliedDate.IsZero() {
+			return job.AppliedDate
+		}
+		return job.StatusChanged
+	case "last_interview":
+		return job.LastInterview
+	default:
+		return job.StatusChanged
+	}
+}
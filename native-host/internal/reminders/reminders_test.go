@@ -0,0 +1,167 @@
+package reminders
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"native-host/internal/config"
+	"native-host/internal/db"
+)
+
+var now = time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(n int) time.Time {
+	return now.AddDate(0, 0, -n)
+}
+
+func TestEvaluate(t *testing.T) {
+	rules := []config.ReminderRule{
+		{Name: "applied", Status: "applied", Since: "applied_date", Days: 7},
+		{Name: "stale", Status: "saved", Since: "updated_at", Days: 14},
+		{Name: "interview", Status: "interview", Since: "last_interview", Days: 3},
+	}
+	key := func(rule string) db.ReminderKey { return db.ReminderKey{JobID: 1, Rule: rule} }
+
+	for _, tc := range []struct {
+		name   string
+		job    db.ReminderCandidate
+		states map[db.ReminderKey]db.ReminderState
+		want   []string // due rules
+	}{
+		{
+			"applied a week ago",
+			db.ReminderCandidate{Status: "applied", AppliedDate: daysAgo(7)},
+			nil,
+			[]string{"applied"},
+		},
+		{
+			"not due yet",
+			db.ReminderCandidate{Status: "applied", AppliedDate: daysAgo(6)},
+			nil,
+			nil,
+		},
+		{
+			"applied date falls back to the status change",
+			db.ReminderCandidate{Status: "applied", StatusChanged: daysAgo(8)},
+			nil,
+			[]string{"applied"},
+		},
+		{
+			"other status",
+			db.ReminderCandidate{Status: "offer", AppliedDate: daysAgo(30), StatusChanged: daysAgo(30)},
+			nil,
+			nil,
+		},
+		{
+			"stale since the status change",
+			db.ReminderCandidate{Status: "saved", StatusChanged: daysAgo(14)},
+			nil,
+			[]string{"stale"},
+		},
+		{
+			"no past interview",
+			db.ReminderCandidate{Status: "interview", StatusChanged: daysAgo(30)},
+			nil,
+			nil,
+		},
+		{
+			"interview without an outcome",
+			db.ReminderCandidate{Status: "interview", LastInterview: daysAgo(4)},
+			nil,
+			[]string{"interview"},
+		},
+		{
+			"snoozed",
+			db.ReminderCandidate{Status: "applied", AppliedDate: daysAgo(10)},
+			map[db.ReminderKey]db.ReminderState{key("applied"): {SnoozedUntil: now.Add(time.Hour)}},
+			nil,
+		},
+		{
+			"snooze ran out",
+			db.ReminderCandidate{Status: "applied", AppliedDate: daysAgo(10)},
+			map[db.ReminderKey]db.ReminderState{key("applied"): {SnoozedUntil: now}},
+			[]string{"applied"},
+		},
+		{
+			"snoozed for another job",
+			db.ReminderCandidate{Status: "applied", AppliedDate: daysAgo(10)},
+			map[db.ReminderKey]db.ReminderState{{JobID: 2, Rule: "applied"}: {SnoozedUntil: now.Add(time.Hour)}},
+			[]string{"applied"},
+		},
+		{
+			"dismissed",
+			db.ReminderCandidate{Status: "saved", StatusChanged: daysAgo(20)},
+			map[db.ReminderKey]db.ReminderState{key("stale"): {DismissedAt: daysAgo(2)}},
+			nil,
+		},
+		{
+			"status changed after the dismissal",
+			db.ReminderCandidate{Status: "saved", StatusChanged: daysAgo(15)},
+			map[db.ReminderKey]db.ReminderState{key("stale"): {DismissedAt: daysAgo(20)}},
+			[]string{"stale"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.job.JobID = 1
+			var got []string
+			for _, r := range Evaluate(rules, []db.ReminderCandidate{tc.job}, tc.states, now) {
+				got = append(got, r.Rule)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Evaluate = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateOrderAndFields(t *testing.T) {
+	rules := []config.ReminderRule{{Name: "applied", Status: "applied", Since: "applied_date", Days: 7, Message: "Follow up"}}
+	jobs := []db.ReminderCandidate{
+		{JobID: 1, Status: "applied", AppliedDate: daysAgo(8)},
+		{JobID: 2, Status: "applied", AppliedDate: daysAgo(12), JobTitle: "Go Engineer", CompanyName: "Acme"},
+	}
+
+	due := Evaluate(rules, jobs, nil, now)
+	if len(due) != 2 || due[0].JobID != 2 || due[1].JobID != 1 {
+		t.Fatalf("Evaluate = %+v, want job 2 then job 1", due)
+	}
+	want := Reminder{
+		JobID:       2,
+		JobTitle:    "Go Engineer",
+		CompanyName: "Acme",
+		Status:      "applied",
+		Rule:        "applied",
+		Message:     "Follow up",
+		Since:       daysAgo(12),
+		DueAt:       daysAgo(5),
+		DaysWaiting: 12,
+	}
+	if due[0] != want {
+		t.Errorf("Evaluate()[0] = %+v, want %+v", due[0], want)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		rules []config.ReminderRule
+		ok    bool
+	}{
+		{"defaults", config.DefaultReminders, true},
+		{"no name", []config.ReminderRule{{Status: "applied", Since: "updated_at", Days: 1}}, false},
+		{"duplicate", []config.ReminderRule{
+			{Name: "a", Status: "applied", Since: "updated_at", Days: 1},
+			{Name: "a", Status: "saved", Since: "updated_at", Days: 1},
+		}, false},
+		{"no status", []config.ReminderRule{{Name: "a", Since: "updated_at", Days: 1}}, false},
+		{"zero days", []config.ReminderRule{{Name: "a", Status: "applied", Since: "updated_at"}}, false},
+		{"unknown anchor", []config.ReminderRule{{Name: "a", Status: "applied", Since: "created_at", Days: 1}}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ValidateRules(tc.rules); (err == nil) != tc.ok {
+				t.Errorf("ValidateRules = %v, want ok %v", err, tc.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package review decides which extractions a person should look at before
+// they are trusted. Jobs flagged for review are left out of analytics until
+// approved, so that one bad extraction doesn't skew the charts.
+package review
+
+import (
+	"sort"
+
+	"native-host/internal/grounding"
+	"native-host/internal/models"
+)
+
+// Reasons are "<kind>:<field>" or a bare kind. Kinds:
+const (
+	Missing    = "missing"    // a key field is empty
+	Invalid    = "invalid"    // models.Validate rejected the value
+	Suspicious = "suspicious" // a number that is possible but unlikely
+	Ungrounded = "ungrounded" // the value wasn't found in the posting text
+	Injection  = "injection"  // the posting contained text aimed at the model
+)
+
+// keyFields are the fields every posting states and analytics group by.
+var keyFields = []string{
+	"metadata.job_title",
+	"company_info.company_name",
+	"metadata.seniority_level",
+	"work_arrangement.workplace_type",
+}
+
+// Limits beyond which numbers are suspicious. Annual salaries under
+// minSalary usually lost a "k" or are hourly or monthly rates.
+const (
+	minSalary          = 1000
+	maxSalary          = 2_000_000
+	maxSalarySpread    = 4 // salary_max over salary_min
+	maxYears           = 25
+	maxInterviewRounds = 12
+)
+
+// Reasons returns why job needs review, sorted, or nil when it doesn't.
+// checks are the job's grounding checks, nil when the posting text is not
+// available.
+func Reasons(job *models.JobPosting, checks []grounding.FieldCheck) []string {
+	set := map[string]bool{}
+	add := func(kind, field string) {
+		if field != "" {
+			kind += ":" + field
+		}
+		set[kind] = true
+	}
+
+	fields := job.SetFields()
+	for _, f := range keyFields {
+		if _, ok := fields[f]; !ok {
+			add(Missing, f)
+		}
+	}
+
+	for _, fe := range job.Validate() {
+		add(Invalid, fe.Field)
+	}
+
+	comp := job.Compensation
+	for _, s := range []struct {
+		field string
+		value int
+	}{
+		{"compensation.salary_min", comp.SalaryMin},
+		{"compensation.salary_max", comp.SalaryMax},
+	} {
+		if s.value > 0 && (s.value < minSalary || s.value > maxSalary) {
+			add(Suspicious, s.field)
+		}
+	}
+	if comp.SalaryMin > 0 && comp.SalaryMax > maxSalarySpread*comp.SalaryMin {
+		add(Suspicious, "compensation.salary")
+	}
+	req := job.Requirements
+	if req.YearsExperienceMin > maxYears || req.YearsExperienceMax > maxYears {
+		add(Suspicious, "requirements.years_experience")
+	}
+	if job.MarketSignals.InterviewRounds > maxInterviewRounds {
+		add(Suspicious, "market_signals.interview_rounds")
+	}
+
+	for _, c := range checks {
+		if !c.Grounded {
+			add(Ungrounded, c.Field)
+		}
+	}
+
+	if len(job.InjectionFlags) > 0 {
+		add(Injection, "")
+	}
+
+	if len(set) == 0 {
+		return nil
+	}
+	reasons := make([]string, 0, len(set))
+	for r := range set {
+		reasons = append(reasons, r)
+	}
+	sort.Strings(reasons)
+	return reasons
+}
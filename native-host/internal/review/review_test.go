@@ -0,0 +1,124 @@
+package review
+
+import (
+	"reflect"
+	"testing"
+
+	"native-host/internal/grounding"
+	"native-host/internal/models"
+)
+
+// goodJob has every key field set and plausible numbers.
+func goodJob() *models.JobPosting {
+	j := &models.JobPosting{}
+	j.Metadata.JobTitle = "Senior Go Engineer"
+	j.Metadata.SeniorityLevel = "Senior"
+	j.CompanyInfo.CompanyName = "Acme"
+	j.WorkArrangement.WorkplaceType = "Remote"
+	j.Compensation.SalaryMin = 80000
+	j.Compensation.SalaryMax = 95000
+	j.Requirements.YearsExperienceMin = 5
+	j.MarketSignals.InterviewRounds = 4
+	return j
+}
+
+func TestReasons(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		edit   func(*models.JobPosting)
+		checks []grounding.FieldCheck
+		want   []string
+	}{
+		{"nothing to review", func(j *models.JobPosting) {}, nil, nil},
+		{
+			"missing key fields",
+			func(j *models.JobPosting) {
+				j.Metadata.JobTitle = ""
+				j.WorkArrangement.WorkplaceType = ""
+			},
+			nil,
+			[]string{"missing:metadata.job_title", "missing:work_arrangement.workplace_type"},
+		},
+		{
+			"invalid enum",
+			func(j *models.JobPosting) { j.Metadata.SeniorityLevel = "Wizard" },
+			nil,
+			[]string{"invalid:metadata.seniority_level"},
+		},
+		{
+			"salary min over max",
+			func(j *models.JobPosting) { j.Compensation.SalaryMin = 120000 },
+			nil,
+			[]string{"invalid:compensation.salary"},
+		},
+		{
+			"salary that lost its k",
+			func(j *models.JobPosting) {
+				j.Compensation.SalaryMin = 80
+				j.Compensation.SalaryMax = 0
+			},
+			nil,
+			[]string{"suspicious:compensation.salary_min"},
+		},
+		{
+			"salary out of range",
+			func(j *models.JobPosting) { j.Compensation.SalaryMax = 2_500_000 },
+			nil,
+			[]string{"suspicious:compensation.salary", "suspicious:compensation.salary_max"},
+		},
+		{
+			"salary spread",
+			func(j *models.JobPosting) { j.Compensation.SalaryMax = 4*80000 + 1 },
+			nil,
+			[]string{"suspicious:compensation.salary"},
+		},
+		{
+			"salary spread at the limit",
+			func(j *models.JobPosting) { j.Compensation.SalaryMax = 4 * 80000 },
+			nil,
+			nil,
+		},
+		{
+			"years and rounds",
+			func(j *models.JobPosting) {
+				j.Requirements.YearsExperienceMax = 30
+				j.MarketSignals.InterviewRounds = 13
+			},
+			nil,
+			[]string{"suspicious:market_signals.interview_rounds", "suspicious:requirements.years_experience"},
+		},
+		{
+			"ungrounded fields",
+			func(j *models.JobPosting) {},
+			[]grounding.FieldCheck{
+				{Field: "compensation.salary_min", Value: "80000", Grounded: true, Confidence: 1},
+				{Field: "requirements.technical_skills.programming_languages", Value: "Rust"},
+				{Field: "requirements.technical_skills.programming_languages", Value: "Zig"},
+			},
+			[]string{"ungrounded:requirements.technical_skills.programming_languages"},
+		},
+		{
+			"injection flags",
+			func(j *models.JobPosting) { j.InjectionFlags = []string{"ignore_instructions", "role_change"} },
+			nil,
+			[]string{"injection"},
+		},
+		{
+			"several, sorted",
+			func(j *models.JobPosting) {
+				j.CompanyInfo.CompanyName = ""
+				j.InjectionFlags = []string{"schema_keys"}
+			},
+			[]grounding.FieldCheck{{Field: "compensation.salary_max", Value: "95000"}},
+			[]string{"injection", "missing:company_info.company_name", "ungrounded:compensation.salary_max"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			job := goodJob()
+			tc.edit(job)
+			if got := Reasons(job, tc.checks); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Reasons = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
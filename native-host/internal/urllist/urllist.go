@@ -0,0 +1,135 @@
+// Package urllist reads lists of job posting URLs from plain text, CSV and
+// browser bookmark exports.
+package urllist
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Format is the layout of an input file.
+type Format string
+
+const (
+	Text      Format = "text"
+	CSV       Format = "csv"
+	Bookmarks Format = "bookmarks"
+)
+
+var urlRe = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// Parse extracts the http(s) URLs from data, in order and without
+// duplicates. The format is guessed from the file name and content when
+// format is empty.
+func Parse(data []byte, name string, format Format) ([]string, error) {
+	if format == "" {
+		format = Detect(data, name)
+	}
+
+	var urls []string
+	switch format {
+	case Bookmarks:
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("parse bookmarks: %w", err)
+		}
+		doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+			href, _ := a.Attr("href")
+			urls = append(urls, href)
+		})
+	case CSV:
+		var err error
+		if urls, err = parseCSV(data); err != nil {
+			return nil, err
+		}
+	case Text:
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			urls = append(urls, urlRe.FindAllString(line, -1)...)
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	return normalize(urls), nil
+}
+
+// Detect guesses the format of a file.
+func Detect(data []byte, name string) Format {
+	head := strings.ToLower(string(data[:min(len(data), 512)]))
+	switch {
+	case strings.Contains(head, "netscape-bookmark-file"), strings.Contains(head, "<html"), strings.Contains(head, "<dl"):
+		return Bookmarks
+	case strings.EqualFold(filepath.Ext(name), ".csv"):
+		return CSV
+	}
+	return Text
+}
+
+// parseCSV reads the column named url/link/href when there is a header,
+// otherwise every cell that looks like a URL.
+func parseCSV(data []byte) ([]string, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	col := -1
+	for i, h := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(h)) {
+		case "url", "link", "href", "job_url", "source_url":
+			col = i
+		}
+	}
+
+	var urls []string
+	for i, rec := range records {
+		if col >= 0 {
+			if i > 0 && col < len(rec) {
+				urls = append(urls, strings.TrimSpace(rec[col]))
+			}
+			continue
+		}
+		for _, cell := range rec {
+			urls = append(urls, urlRe.FindAllString(cell, -1)...)
+		}
+	}
+	return urls, nil
+}
+
+// normalize drops non-http entries and duplicates, and trims punctuation
+// picked up from surrounding prose, e.g. "(see https://…)." .
+func normalize(raw []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range raw {
+		s = strings.TrimRight(strings.TrimSpace(s), ".,;:!?)]}")
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			continue
+		}
+		u.Fragment = ""
+		key := u.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, key)
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+package urllist
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		file   string
+		data   string
+		format Format
+		want   []string
+	}{
+		{
+			"text with comments and blank lines",
+			"jobs.txt",
+			`# saved from the job board
+https://boards.greenhouse.io/acme/jobs/1
+
+   # https://jobs.lever.co/acme/commented-out
+https://jobs.lever.co/acme/2
+`,
+			"",
+			[]string{"https://boards.greenhouse.io/acme/jobs/1", "https://jobs.lever.co/acme/2"},
+		},
+		{
+			"urls in prose",
+			"notes.txt",
+			"Looks good (see https://acme.example/jobs/3). Also https://acme.example/jobs/4, and http://acme.example/jobs/5!",
+			"",
+			[]string{"https://acme.example/jobs/3", "https://acme.example/jobs/4", "http://acme.example/jobs/5"},
+		},
+		{
+			"duplicates and fragments",
+			"jobs.txt",
+			"https://acme.example/jobs/1\nhttps://acme.example/jobs/1#apply\nhttps://acme.example/jobs/1.\nhttps://acme.example/jobs/2",
+			"",
+			[]string{"https://acme.example/jobs/1", "https://acme.example/jobs/2"},
+		},
+		{
+			"invalid urls",
+			"jobs.txt",
+			"ftp://acme.example/jobs/1\nhttps://\nhttp://%zz/jobs\nwww.acme.example/jobs/2\nhttps://acme.example/jobs/3",
+			"",
+			[]string{"https://acme.example/jobs/3"},
+		},
+		{
+			"csv with a url column",
+			"export.csv",
+			"Company,URL,Notes\nAcme, https://acme.example/jobs/1 ,see https://acme.example/other\nGlobex,,\nInitech,not a url,\n",
+			"",
+			[]string{"https://acme.example/jobs/1"},
+		},
+		{
+			"csv without a header",
+			"export.csv",
+			"Acme,https://acme.example/jobs/1\nGlobex,\"https://globex.example/jobs/2, https://globex.example/jobs/3\"\n",
+			"",
+			[]string{"https://acme.example/jobs/1", "https://globex.example/jobs/2", "https://globex.example/jobs/3"},
+		},
+		{
+			"bookmarks",
+			"bookmarks.html",
+			`<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<DL><p>
+  <DT><H3>Jobs</H3>
+  <DL><p>
+    <DT><A HREF="https://acme.example/jobs/1">Go Engineer</A>
+    <DT><A HREF="javascript:void(0)">Bookmarklet</A>
+    <DT><A HREF="https://acme.example/jobs/1">Go Engineer again</A>
+  </DL><p>
+</DL>`,
+			"",
+			[]string{"https://acme.example/jobs/1"},
+		},
+		{
+			"explicit format wins over the name",
+			"jobs.csv",
+			"# not csv\nhttps://acme.example/jobs/1",
+			Text,
+			[]string{"https://acme.example/jobs/1"},
+		},
+		{"empty", "jobs.txt", "", "", nil},
+		{"comments only", "jobs.txt", "# nothing yet\n\n", "", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse([]byte(tc.data), tc.file, tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Parse = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	if _, err := Parse([]byte("https://acme.example/jobs/1"), "jobs.txt", "xml"); err == nil {
+		t.Error("Parse with an unknown format succeeded")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		name, data string
+		want       Format
+	}{
+		{"jobs.txt", "https://acme.example/jobs/1", Text},
+		{"jobs", "https://acme.example/jobs/1", Text},
+		{"Export.CSV", "url\nhttps://acme.example/jobs/1", CSV},
+		{"bookmarks.txt", "<!DOCTYPE NETSCAPE-Bookmark-file-1>", Bookmarks},
+		{"export.csv", "<html><body><a href=\"https://acme.example\">x</a>", Bookmarks},
+	} {
+		if got := Detect([]byte(tc.data), tc.name); got != tc.want {
+			t.Errorf("Detect(%q) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package webpage
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// The scoring below is a trimmed-down version of Mozilla Readability:
+// strip obvious chrome, score text blocks, propagate the scores to their
+// ancestors and keep the best-scoring container.
+
+var (
+	// Matched against whole class and id tokens, split on whitespace, "-"
+	// and "_", so that "shared" or "unrelated" don't count.
+	hardNegative = regexp.MustCompile(`^(?:cookies?|consent|gdpr|similar|related|recommend(?:ed|ations?)?|newsletter|share|sharing|social|breadcrumbs?|popup|modal|skip)$`)
+	// Matched anywhere in the class and id.
+	softNegative = regexp.MustCompile(`(?i)footer|header|nav|menu|sidebar|banner|widget|promo|sponsor|comment|login|signup|masthead`)
+	// Keeps an element that matched either of the above.
+	positive = regexp.MustCompile(`(?i)content|article|main|job|posting|description|detail|body|entry|text`)
+)
+
+// Elements that never hold posting text.
+const chromeSelector = `script, style, noscript, iframe, svg, canvas, form, nav, header, footer, aside, button, select, input, textarea, dialog, [role="navigation"], [role="banner"], [role="contentinfo"], [role="dialog"], [aria-hidden="true"], [hidden]`
+
+// Blocks whose text is scored.
+const scoredSelector = `p, li, td, pre, blockquote, dd, h2, h3, h4, div`
+
+const (
+	minBlockChars    = 25
+	minArticleChars  = 200
+	siblingThreshold = 0.2
+)
+
+// MainText returns the readable text of the posting on the page, falling
+// back to the whole body when no container stands out.
+func MainText(doc *goquery.Document) string {
+	doc = goquery.CloneDocument(doc)
+	stripChrome(doc)
+
+	body := doc.Find("body")
+	if body.Length() == 0 {
+		body = doc.Selection
+	}
+
+	best := bestCandidate(body)
+	if best == nil {
+		return renderText(body)
+	}
+
+	text := renderText(best)
+	if len(text) < minArticleChars {
+		return renderText(body)
+	}
+	return text
+}
+
+func stripChrome(doc *goquery.Document) {
+	doc.Find(chromeSelector).Remove()
+
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "html" || goquery.NodeName(s) == "body" {
+			return
+		}
+		id, _ := s.Attr("id")
+		class, _ := s.Attr("class")
+		attrs := id + " " + class
+		if strings.TrimSpace(attrs) == "" {
+			return
+		}
+		if (hasToken(attrs, hardNegative) || softNegative.MatchString(attrs)) && !positive.MatchString(attrs) {
+			s.Remove()
+		}
+	})
+}
+
+// hasToken reports whether re matches one of the lower-cased tokens of a
+// class or id attribute.
+func hasToken(attrs string, re *regexp.Regexp) bool {
+	tokens := strings.FieldsFunc(strings.ToLower(attrs), func(r rune) bool {
+		return r == '-' || r == '_' || unicode.IsSpace(r)
+	})
+	for _, t := range tokens {
+		if re.MatchString(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// bestCandidate scores text blocks and returns the container holding most
+// of the posting, merged with qualifying siblings. It returns nil when
+// nothing scored.
+func bestCandidate(root *goquery.Selection) *goquery.Selection {
+	scores := make(map[*html.Node]float64)
+
+	root.Find(scoredSelector).Each(func(_ int, s *goquery.Selection) {
+		// A div only counts as a block if it holds text directly rather
+		// than through nested blocks, otherwise everything is counted twice.
+		if goquery.NodeName(s) == "div" && s.Children().Filter(scoredSelector).Length() > 0 {
+			return
+		}
+
+		text := collapseSpaces(s.Text())
+		if len(text) < minBlockChars {
+			return
+		}
+
+		score := 1.0 + float64(strings.Count(text, ","))
+		score += min(float64(len(text))/100, 3)
+
+		parent := s.Parent()
+		if parent.Length() == 0 {
+			return
+		}
+		scores[parent.Get(0)] += score
+		if grand := parent.Parent(); grand.Length() > 0 {
+			scores[grand.Get(0)] += score / 2
+		}
+	})
+
+	if len(scores) == 0 {
+		return nil
+	}
+
+	type candidate struct {
+		node  *html.Node
+		score float64
+	}
+	candidates := make([]candidate, 0, len(scores))
+	for node, score := range scores {
+		sel := goquery.NewDocumentFromNode(node).Selection
+		score += classWeight(sel)
+		score *= 1 - linkDensity(sel)
+		candidates = append(candidates, candidate{node, score})
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].score > candidates[b].score
+	})
+
+	top := candidates[0]
+	if top.score <= 0 {
+		return nil
+	}
+
+	// Pull in siblings that scored well too, e.g. a "Requirements" section
+	// rendered as a separate container next to the description.
+	final := map[*html.Node]float64{}
+	for _, c := range candidates {
+		final[c.node] = c.score
+	}
+	threshold := max(10, top.score*siblingThreshold)
+
+	parent := top.node.Parent
+	if parent == nil {
+		return goquery.NewDocumentFromNode(top.node).Selection
+	}
+
+	var nodes []*html.Node
+	for sib := parent.FirstChild; sib != nil; sib = sib.NextSibling {
+		if sib == top.node || (sib.Type == html.ElementNode && final[sib] >= threshold) {
+			nodes = append(nodes, sib)
+		}
+	}
+	return goquery.NewDocumentFromNode(nodes[0]).Selection.AddNodes(nodes[1:]...)
+}
+
+func classWeight(s *goquery.Selection) float64 {
+	id, _ := s.Attr("id")
+	class, _ := s.Attr("class")
+	attrs := id + " " + class
+	weight := 0.0
+	if positive.MatchString(attrs) {
+		weight += 25
+	}
+	if softNegative.MatchString(attrs) {
+		weight -= 25
+	}
+	return weight
+}
+
+// linkDensity is the share of a node's text that sits inside links.
+func linkDensity(s *goquery.Selection) float64 {
+	total := len(collapseSpaces(s.Text()))
+	if total == 0 {
+		return 0
+	}
+	linked := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linked += len(collapseSpaces(a.Text()))
+	})
+	return float64(linked) / float64(total)
+}
+
+var blockElements = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "fieldset": true, "figcaption": true, "figure": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"hr": true, "li": true, "main": true, "ol": true, "p": true, "pre": true,
+	"section": true, "table": true, "tr": true, "ul": true,
+}
+
+// renderText converts a selection to plain text, keeping paragraph breaks,
+// putting headings on their own lines and bulleting list items.
+func renderText(s *goquery.Selection) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "br":
+				b.WriteString("\n")
+				return
+			case "li":
+				b.WriteString("\n• ")
+			default:
+				if blockElements[n.Data] {
+					b.WriteString("\n\n")
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && blockElements[n.Data] && n.Data != "li" {
+			b.WriteString("\n\n")
+		}
+	}
+	for _, n := range s.Nodes {
+		walk(n)
+	}
+	return tidyLines(b.String())
+}
+
+// tidyLines collapses runs of spaces within lines and of blank lines.
+func tidyLines(s string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	blank := true
+	for _, line := range lines {
+		line = collapseSpaces(line)
+		if line == "" || line == "•" {
+			if !blank {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		out = append(out, line)
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
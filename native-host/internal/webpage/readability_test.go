@@ -0,0 +1,87 @@
+package webpage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func loadFixture(t *testing.T, name string) *goquery.Document {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestStripChrome(t *testing.T) {
+	doc := loadFixture(t, "readability.html")
+	stripChrome(doc)
+
+	for _, class := range []string{"shared-layout", "job-modality", "posting-body", "unrelated", "social-impact-job"} {
+		if doc.Find("."+class).Length() == 0 {
+			t.Errorf(".%s was removed", class)
+		}
+	}
+	for _, sel := range []string{"#cookie-consent", ".skip-link", ".social-share", ".related_links", ".newsletter"} {
+		if doc.Find(sel).Length() > 0 {
+			t.Errorf("%s was kept", sel)
+		}
+	}
+}
+
+func TestMainText(t *testing.T) {
+	text := MainText(loadFixture(t, "readability.html"))
+	for _, want := range []string{
+		"Senior Go Engineer to build the payment platform",
+		"Five or more years of backend experience",
+		"Workplace: hybrid",
+		"social impact programme",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("MainText lacks %q:\n%s", want, text)
+		}
+	}
+	for _, unwanted := range []string{
+		"cookies",
+		"Skip to content",
+		"Share this job",
+		"Similar jobs",
+		"newsletter",
+	} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("MainText contains %q:\n%s", unwanted, text)
+		}
+	}
+}
+
+func TestHasToken(t *testing.T) {
+	for _, tc := range []struct {
+		attrs string
+		want  bool
+	}{
+		{"shared-layout", false},
+		{"unrelated", false},
+		{"job-modality", false},
+		{"sharepoint-docs", false},
+		{"", false},
+		{" social-impact-job", true},
+		{"cookie-banner", true},
+		{"Related_Jobs", true},
+		{"main share-buttons", true},
+		{"page-modal\twide", true},
+	} {
+		if got := hasToken(tc.attrs, hardNegative); got != tc.want {
+			t.Errorf("hasToken(%q) = %v, want %v", tc.attrs, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package webpage fetches job posting pages and reduces them to the text
+// of the posting itself, without navigation and other page chrome.
+package webpage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxPageBytes caps the size of a fetched page.
+const maxPageBytes = 5 << 20
+
+// Page is a fetched HTML document.
+type Page struct {
+	URL      string // as requested
+	FinalURL string // after redirects
+	Doc      *goquery.Document
+}
+
+// Fetch downloads rawURL with client (http.DefaultClient when nil) and
+// parses it as HTML.
+func Fetch(ctx context.Context, client *http.Client, userAgent, rawURL string) (*Page, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: status %d", rawURL, resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return nil, fmt.Errorf("fetch %s: unsupported content type %q", rawURL, ct)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, maxPageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", rawURL, err)
+	}
+
+	return &Page{URL: rawURL, FinalURL: resp.Request.URL.String(), Doc: doc}, nil
+}
+
+// Title returns the page's og:title, falling back to <title> and the first
+// <h1>.
+func Title(doc *goquery.Document) string {
+	if t, ok := doc.Find(`meta[property="og:title"]`).Attr("content"); ok && strings.TrimSpace(t) != "" {
+		return strings.TrimSpace(t)
+	}
+	if t := strings.TrimSpace(doc.Find("title").First().Text()); t != "" {
+		return t
+	}
+	return collapseSpaces(doc.Find("h1").First().Text())
+}
+
+// FormatForExtraction lays out a page the same way the extension's content
+// script does, so the prompt and utils.ExtractURL see a familiar shape.
+func FormatForExtraction(page *Page) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "URL: %s\n", page.FinalURL)
+	b.WriteString("SOURCE: fetched by native host\n\n")
+	if title := Title(page.Doc); title != "" {
+		b.WriteString(title)
+		b.WriteString("\n\n")
+	}
+	b.WriteString(MainText(page.Doc))
+	b.WriteString("\n")
+	return b.String()
+}